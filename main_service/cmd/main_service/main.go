@@ -3,14 +3,52 @@ package main
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"main_service/internal/config"
+	"main_service/internal/http-server/handlers/availability"
+	"main_service/internal/http-server/handlers/blocks"
+	bookseries "main_service/internal/http-server/handlers/book_series"
 	booktable "main_service/internal/http-server/handlers/book_table"
+	bookingapproval "main_service/internal/http-server/handlers/booking_approval"
+	bookinghistory "main_service/internal/http-server/handlers/booking_history"
+	bookingstatus "main_service/internal/http-server/handlers/booking_status"
+	"main_service/internal/http-server/handlers/calendar"
 	cancelbooking "main_service/internal/http-server/handlers/cancel_booking"
+	cancelseries "main_service/internal/http-server/handlers/cancel_series"
+	checkin "main_service/internal/http-server/handlers/check_in"
+	"main_service/internal/http-server/handlers/combinations"
+	"main_service/internal/http-server/handlers/customers"
+	"main_service/internal/http-server/handlers/events"
+	exportbookings "main_service/internal/http-server/handlers/export_bookings"
+	"main_service/internal/http-server/handlers/feedback"
+	"main_service/internal/http-server/handlers/floorplan"
 	getbookings "main_service/internal/http-server/handlers/get_bookings"
+	"main_service/internal/http-server/handlers/loyalty"
+	"main_service/internal/http-server/handlers/menu"
 	bookingsrv "main_service/internal/http-server/handlers/middleware/booking"
+	calendarsrv "main_service/internal/http-server/handlers/middleware/calendar"
+	customersrv "main_service/internal/http-server/handlers/middleware/customers"
+	eventsrv "main_service/internal/http-server/handlers/middleware/events"
+	feedbacksrv "main_service/internal/http-server/handlers/middleware/feedback"
+	loyaltysrv "main_service/internal/http-server/handlers/middleware/loyalty"
+	menusrv "main_service/internal/http-server/handlers/middleware/menu"
+	promosrv "main_service/internal/http-server/handlers/middleware/promo"
+	reportsrv "main_service/internal/http-server/handlers/middleware/reports"
+	specialeventsrv "main_service/internal/http-server/handlers/middleware/special_events"
+	tablesrv "main_service/internal/http-server/handlers/middleware/tables"
+	webhooksrv "main_service/internal/http-server/handlers/middleware/webhooks"
+	"main_service/internal/http-server/handlers/payments"
+	preorder "main_service/internal/http-server/handlers/pre_order"
+	promocodes "main_service/internal/http-server/handlers/promo_codes"
+	"main_service/internal/http-server/handlers/reports"
+	specialevents "main_service/internal/http-server/handlers/special_events"
+	"main_service/internal/http-server/handlers/webhooks"
+	zoneschedules "main_service/internal/http-server/handlers/zone_schedules"
 	"main_service/internal/lib/jwt"
 	"main_service/internal/lib/logger/sl"
+	"main_service/internal/lib/payment"
+	"main_service/internal/lib/webhook"
 	"main_service/internal/rabbitmq"
 	"main_service/internal/storage/postgres"
 	"main_service/internal/storage/redis"
@@ -30,6 +68,12 @@ const (
 	envProd  = "prod"
 )
 
+// paymentExpiryInterval - как часто отменяются брони с неоплаченным депозитом.
+const paymentExpiryInterval = time.Minute
+
+// restoreCacheTimeout - сколько при старте ждать восстановления броней в Redis.
+const restoreCacheTimeout = time.Minute
+
 func main() {
 	cfg := config.MustLoad("./config/local.yaml")
 	log := setupLogger(cfg.Env)
@@ -76,19 +120,138 @@ func main() {
 		os.Exit(1)
 	}
 
-	bookingService := bookingsrv.NewBookingService(postgresRepo, redisRepo, rabbitMQClient)
+	paymentProvider, err := setupPaymentProvider(cfg)
+	if err != nil {
+		log.Error("failed to init payment provider", sl.Err(err))
+		os.Exit(1)
+	}
+
+	bookingService := bookingsrv.NewBookingService(
+		postgresRepo,
+		redisRepo,
+		rabbitMQClient,
+		cfg.Restaurant,
+		cfg.Approval,
+		cfg.Pacing,
+		paymentProvider,
+		cfg.Deposits,
+		cfg.Loyalty,
+		cfg.PreOrders,
+		cfg.Feedback,
+	)
+
+	restoreCtx, cancelRestore := context.WithTimeout(context.Background(), restoreCacheTimeout)
+	restored, err := bookingService.RestoreCache(restoreCtx)
+	cancelRestore()
+	if err != nil {
+		log.Error("failed to restore bookings in redis", sl.Err(err))
+		os.Exit(1)
+	}
+	log.Info("bookings restored in redis", slog.Int("count", restored))
+
+	go bookingService.RunPaymentExpiry(context.Background(), log, paymentExpiryInterval)
+	reportService := reportsrv.NewReportService(postgresRepo, cfg.Restaurant)
+	calendarService := calendarsrv.NewCalendarService(postgresRepo, cfg.Restaurant)
+	tableService := tablesrv.NewTableService(postgresRepo, redisRepo, cfg.Restaurant, cfg.Pacing)
+	loyaltyService := loyaltysrv.NewLoyaltyService(postgresRepo)
+	promoService := promosrv.NewPromoService(postgresRepo)
+	menuService := menusrv.NewMenuService(postgresRepo)
+	specialEventService := specialeventsrv.NewSpecialEventService(postgresRepo, cfg.Restaurant)
+	feedbackService := feedbacksrv.NewFeedbackService(postgresRepo, cfg.Feedback)
+	customerService := customersrv.NewCustomerService(postgresRepo)
+
+	eventBroker := eventsrv.NewBroker(log, postgresRepo)
+	go eventBroker.Run(context.Background())
+
+	webhookService := webhooksrv.NewWebhookService(log, postgresRepo, webhook.NewClient(cfg.Webhooks.Timeout), cfg.Webhooks)
+	go webhookService.Run(context.Background())
 
 	// * Routing
 	r := chi.NewRouter()
 	r.Use(middleware.RequestID)
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
-	r.Use(jwt.AuthMiddleware(cfg.AppSecret))
+
+	// * Public handlers
+	r.Get("/calendar/{token}.ics", calendar.Feed(log, ssoClient, calendarService))
+	r.Post("/payments/callback", payments.Callback(log, bookingService))
+	r.Get("/menu", menu.Get(log, menuService))
+	r.Get("/special-events", specialevents.List(log, specialEventService))
+	r.Post("/feedback/{id}", feedback.Submit(log, feedbackService))
 
 	// * Handlers
-	r.Post("/book", booktable.New(log, ssoClient, bookingService))
-	r.Post("/cancel", cancelbooking.New(log, ssoClient, bookingService, postgresRepo))
-	r.Get("/bookings", getbookings.New(log, ssoClient, bookingService))
+	r.Group(func(r chi.Router) {
+		r.Use(jwt.AuthMiddleware(cfg.AppSecret))
+
+		r.Post("/book", booktable.New(log, ssoClient, bookingService))
+		r.Post("/book/recurring", bookseries.New(log, ssoClient, bookingService))
+		r.Post("/cancel", cancelbooking.New(log, ssoClient, bookingService, postgresRepo))
+		r.Post("/series/{id}/cancel", cancelseries.New(log, ssoClient, bookingService))
+		r.Get("/bookings", getbookings.New(log, ssoClient, bookingService))
+		r.Get("/bookings/export", exportbookings.New(log, ssoClient, bookingService))
+		r.Get("/events/bookings", events.Bookings(log, ssoClient, eventBroker))
+		r.Get("/bookings/{id}/history", bookinghistory.New(log, ssoClient, bookingService))
+		r.Get("/bookings/{id}/ics", calendar.BookingICS(log, ssoClient, calendarService))
+		r.Post("/bookings/{id}/status", bookingstatus.New(log, ssoClient, bookingService))
+		r.Post("/bookings/{id}/approve", bookingapproval.Approve(log, ssoClient, bookingService))
+		r.Post("/bookings/{id}/reject", bookingapproval.Reject(log, ssoClient, bookingService))
+		r.Post("/bookings/{id}/check-in", checkin.New(log, ssoClient, bookingService))
+		r.Get("/bookings/{id}/pre-order", preorder.Get(log, ssoClient, bookingService))
+		r.Put("/bookings/{id}/pre-order", preorder.Update(log, ssoClient, bookingService))
+
+		r.Get("/combinations", combinations.List(log, tableService))
+		r.Post("/combinations", combinations.Create(log, ssoClient, tableService))
+		r.Delete("/combinations/{id}", combinations.Delete(log, ssoClient, tableService))
+		r.Get("/tables/availability", availability.New(log, tableService))
+
+		r.Get("/floor-plan", floorplan.Get(log, ssoClient, tableService))
+		r.Put("/floor-plan/tables/{id}", floorplan.SaveTable(log, ssoClient, tableService))
+
+		r.Get("/blocks", blocks.List(log, ssoClient, tableService))
+		r.Post("/blocks", blocks.Create(log, ssoClient, tableService))
+		r.Delete("/blocks/{id}", blocks.Delete(log, ssoClient, tableService))
+
+		r.Get("/zones/schedules", zoneschedules.List(log, ssoClient, tableService))
+		r.Post("/zones/{zone}/schedules", zoneschedules.Create(log, ssoClient, tableService))
+		r.Delete("/zones/schedules/{id}", zoneschedules.Delete(log, ssoClient, tableService))
+
+		r.Get("/me/loyalty", loyalty.Me(log, loyaltyService))
+		r.Get("/users/{id}/loyalty", loyalty.Get(log, ssoClient, loyaltyService))
+		r.Post("/users/{id}/loyalty/adjustments", loyalty.Adjust(log, ssoClient, loyaltyService))
+		r.Get("/users/{id}/profile", customers.Get(log, ssoClient, customerService))
+		r.Put("/users/{id}/profile", customers.Update(log, ssoClient, customerService))
+
+		r.Post("/menu/categories", menu.CreateCategory(log, ssoClient, menuService))
+		r.Put("/menu/categories/{id}", menu.UpdateCategory(log, ssoClient, menuService))
+		r.Delete("/menu/categories/{id}", menu.DeleteCategory(log, ssoClient, menuService))
+		r.Post("/menu/dishes", menu.CreateDish(log, ssoClient, menuService))
+		r.Put("/menu/dishes/{id}", menu.UpdateDish(log, ssoClient, menuService))
+		r.Delete("/menu/dishes/{id}", menu.DeleteDish(log, ssoClient, menuService))
+
+		r.Get("/promo-codes", promocodes.List(log, ssoClient, promoService))
+		r.Post("/promo-codes", promocodes.Create(log, ssoClient, promoService))
+		r.Delete("/promo-codes/{id}", promocodes.Delete(log, ssoClient, promoService))
+
+		r.Post("/special-events", specialevents.Create(log, ssoClient, specialEventService))
+		r.Delete("/special-events/{id}", specialevents.Delete(log, ssoClient, specialEventService))
+		r.Get("/special-events/{id}/attendees", specialevents.Attendees(log, ssoClient, specialEventService))
+		r.Post("/special-events/{id}/reservations", specialevents.Reserve(log, specialEventService))
+		r.Delete("/special-events/{id}/reservations", specialevents.CancelReservation(log, specialEventService))
+
+		r.Post("/calendar/token", calendar.IssueToken(log, ssoClient, calendarService, cfg.HTTPServer.PublicURL))
+
+		r.Get("/webhooks", webhooks.List(log, ssoClient, webhookService))
+		r.Post("/webhooks", webhooks.Create(log, ssoClient, webhookService))
+		r.Delete("/webhooks/{id}", webhooks.Delete(log, ssoClient, webhookService))
+		r.Post("/webhooks/{id}/enable", webhooks.Enable(log, ssoClient, webhookService))
+		r.Get("/webhooks/{id}/attempts", webhooks.Attempts(log, ssoClient, webhookService))
+
+		r.Get("/reports/covers", reports.Covers(log, ssoClient, reportService))
+		r.Get("/reports/utilisation", reports.Utilisation(log, ssoClient, reportService))
+		r.Get("/reports/rates", reports.Rates(log, ssoClient, reportService))
+		r.Get("/reports/tables", reports.BusiestTables(log, ssoClient, reportService))
+		r.Get("/reports/ratings", reports.Ratings(log, ssoClient, reportService))
+	})
 
 	srv := &http.Server{
 		Addr:         cfg.HTTPServer.Address,
@@ -125,3 +288,24 @@ func setupLogger(env string) *slog.Logger {
 
 	return log
 }
+
+// setupPaymentProvider подключает платёжный провайдер для депозитов. Фейковый провайдер ничего не списывает
+// и любой платёж у него проходит, поэтому он разрешён только при локальном запуске.
+func setupPaymentProvider(cfg *config.Config) (payment.Provider, error) {
+	if cfg.Deposits.CallbackSecret == "" {
+		return nil, payment.ErrEmptySecret
+	}
+
+	switch cfg.Deposits.Provider {
+	case "fake":
+		if cfg.Env != envLocal {
+			return nil, fmt.Errorf("fake payment provider is not allowed in %s", cfg.Env)
+		}
+
+		return payment.NewFake(cfg.Deposits.CallbackSecret), nil
+	case "":
+		return nil, errors.New("payment provider is not configured")
+	default:
+		return nil, fmt.Errorf("unknown payment provider %q", cfg.Deposits.Provider)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"log"
 	"os"
 	"time"
+	// Часовые пояса встроены в бинарник: в образе сервиса может не быть tzdata.
+	_ "time/tzdata"
 
 	"github.com/ilyakaznacheev/cleanenv"
 )
@@ -16,12 +18,22 @@ type Config struct {
 	Postgres   `yaml:"postgres"`
 	Redis      `yaml:"redis"`
 	RabbitMQ   `yaml:"rabbitmq"`
+	Restaurant `yaml:"restaurant"`
+	Approval   `yaml:"approval"`
+	Pacing     `yaml:"pacing"`
+	Deposits   `yaml:"deposits"`
+	Loyalty    `yaml:"loyalty"`
+	PreOrders  `yaml:"pre_orders"`
+	Feedback   `yaml:"feedback"`
+	Webhooks   `yaml:"webhooks"`
 }
 
 type HTTPServer struct {
 	Address     string        `yaml:"address" env-default:"localhost:8080"`
 	Timeout     time.Duration `yaml:"timeout" env-default:"4s"`
 	IdleTimeout time.Duration `yaml:"idle_timeout" env-default:"60s"`
+	// PublicURL - внешний адрес сервиса, используется в ссылках, которые уходят клиентам.
+	PublicURL string `yaml:"public_url" env-default:"http://localhost:8082"`
 }
 
 type Client struct {
@@ -54,6 +66,149 @@ type RabbitMQ struct {
 	QueueName string `yaml:"queue_name" env-default:"notifications_queue"`
 }
 
+type Restaurant struct {
+	TablesCount  int           `yaml:"tables_count" env-default:"10"`
+	OpeningHours time.Duration `yaml:"opening_hours" env-default:"12h"`
+	// Timezone - часовой пояс ресторана, Location - он же, загруженный в MustLoad.
+	Timezone string         `yaml:"timezone" env-default:"Europe/Moscow"`
+	Location *time.Location `yaml:"-" env:"-"`
+	// BookingDuration - длительность брони, если не подошло ни одно правило из TurnTimes.
+	BookingDuration time.Duration `yaml:"booking_duration" env-default:"2h"`
+	// BookedSoonWindow - за сколько до визита стол показывается на плане зала как скоро занятый.
+	BookedSoonWindow time.Duration `yaml:"booked_soon_window" env-default:"1h"`
+	// CleaningBuffer - сколько времени после ухода гостей нужно, чтобы подготовить стол к следующей брони.
+	CleaningBuffer time.Duration `yaml:"cleaning_buffer" env-default:"15m"`
+	// DayParts - части дня, на которые ссылаются правила длительности.
+	DayParts []DayPart `yaml:"day_parts"`
+	// TurnTimes - правила длительности брони. Применяется первое подходящее правило.
+	TurnTimes []TurnTime `yaml:"turn_times"`
+}
+
+// InLocation возвращает время, прочитанное из Postgres, по часам ресторана. Время брони хранится
+// без часового пояса, и pgx возвращает те же часы и минуты в UTC.
+func (r Restaurant) InLocation(t time.Time) time.Time {
+	loc := r.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+}
+
+// Local переводит момент t в пояс ресторана. Время, которое сравнивается с колонками без пояса,
+// нужно передавать так: pgx записывает часы и минуты t и отбрасывает пояс.
+func (r Restaurant) Local(t time.Time) time.Time {
+	if r.Location == nil {
+		return t
+	}
+
+	return t.In(r.Location)
+}
+
+// DayPart - часть дня, например обед или ужин. Бронь относится к части дня по времени начала,
+// время задаётся в формате HH:MM, To не включается.
+type DayPart struct {
+	Name string `yaml:"name"`
+	From string `yaml:"from"`
+	To   string `yaml:"to"`
+}
+
+// TurnTime - сколько длится бронь компании от MinPartySize до MaxPartySize гостей в часть дня DayPart.
+// Нулевой MaxPartySize - без ограничения сверху, пустой DayPart - в любое время.
+type TurnTime struct {
+	MinPartySize int16         `yaml:"min_party_size"`
+	MaxPartySize int16         `yaml:"max_party_size"`
+	DayPart      string        `yaml:"day_part"`
+	Duration     time.Duration `yaml:"duration"`
+}
+
+// Approval задаёт, какие брони не подтверждаются автоматически и ждут решения админа.
+type Approval struct {
+	// PartySizeThreshold - брони больше чем на столько гостей требуют подтверждения, 0 - без ограничения.
+	PartySizeThreshold int `yaml:"party_size_threshold" env-default:"8"`
+	// PrivateTables - столы в отдельных залах, любая бронь которых требует подтверждения.
+	PrivateTables []int16 `yaml:"private_tables"`
+}
+
+// Pacing ограничивает, сколько гостей может прийти за один интервал, чтобы кухня успевала.
+type Pacing struct {
+	Interval time.Duration `yaml:"interval" env-default:"15m"`
+	// Limits - ограничения по частям дня из Restaurant.DayParts. Применяется первое подходящее,
+	// если не подошло ни одно - ограничения нет.
+	Limits []PacingLimit `yaml:"limits"`
+}
+
+// PacingLimit - сколько гостей и броней можно принять за интервал в часть дня DayPart.
+// Пустой DayPart - в любое время, нулевой лимит - без ограничения.
+type PacingLimit struct {
+	DayPart     string `yaml:"day_part"`
+	MaxCovers   int    `yaml:"max_covers"`
+	MaxBookings int    `yaml:"max_bookings"`
+}
+
+// Deposits задаёт, для каких броней нужен депозит, и условия его возврата.
+type Deposits struct {
+	// PartySizeThreshold - с какого размера компании нужен депозит, 0 - по размеру не требуется.
+	PartySizeThreshold int `yaml:"party_size_threshold" env-default:"6"`
+	// Weekdays и DayPart - когда депозит нужен для любой брони, например в пятницу вечером.
+	// Дни недели - по-английски в нижнем регистре, пустой DayPart - весь день.
+	Weekdays []string `yaml:"weekdays"`
+	DayPart  string   `yaml:"day_part"`
+	// AmountPerGuest - депозит за одного гостя в копейках.
+	AmountPerGuest int64  `yaml:"amount_per_guest" env-default:"100000"`
+	Currency       string `yaml:"currency" env-default:"RUB"`
+	// PaymentTimeout - сколько бронь ждёт оплаты, прежде чем отменится.
+	PaymentTimeout time.Duration `yaml:"payment_timeout" env-default:"30m"`
+	// RefundWindow - не позже чем за сколько до визита нужно отменить бронь, чтобы вернуть депозит.
+	RefundWindow time.Duration `yaml:"refund_window" env-default:"24h"`
+	// Provider - платёжный провайдер. Пока есть только fake - фейковый провайдер в памяти, который допустим
+	// только при env: local. Реальный провайдер ещё не подключён, поэтому в остальных окружениях сервис не запустится.
+	Provider string `yaml:"provider" env:"PAYMENT_PROVIDER"`
+	// CallbackSecret - секрет, которым платёжный провайдер подписывает уведомления об оплате.
+	// Задаётся только через окружение, без него сервис не запускается.
+	CallbackSecret string `yaml:"-" env:"PAYMENT_CALLBACK_SECRET" env-required:"true"`
+}
+
+// Loyalty задаёт, сколько баллов лояльности начисляется за визит и списывается за неявку.
+type Loyalty struct {
+	// PointsPerGuest - сколько баллов получает гость за каждого человека в компании, когда визит завершён.
+	PointsPerGuest int `yaml:"points_per_guest" env-default:"10"`
+	// NoShowPenalty - сколько баллов списывается, если гость не пришёл. Баланс может стать отрицательным.
+	NoShowPenalty int `yaml:"no_show_penalty" env-default:"50"`
+}
+
+// PreOrders - правила предзаказа блюд вместе с бронью.
+type PreOrders struct {
+	// Cutoff - не позже чем за сколько до визита гость может изменить предзаказ.
+	Cutoff time.Duration `yaml:"cutoff" env-default:"24h"`
+	// MaxItemsPerGuest - сколько порций всего можно заказать на одного гостя.
+	MaxItemsPerGuest int `yaml:"max_items_per_guest" env-default:"4"`
+}
+
+// Feedback - запрос отзыва у гостя после визита.
+type Feedback struct {
+	// PageURL - страница клиентского приложения с формой отзыва, на неё ведёт ссылка из письма.
+	// Страница отправляет форму на POST /feedback/{id} с параметрами ссылки.
+	PageURL string `yaml:"page_url" env:"FEEDBACK_PAGE_URL" env-required:"true"`
+	// LinkSecret - секрет, которым подписываются ссылки на отзыв в письмах гостям.
+	// Задаётся только через окружение, без него сервис не запускается.
+	LinkSecret string `yaml:"-" env:"FEEDBACK_LINK_SECRET" env-required:"true"`
+	// LinkTTL - сколько после визита действует ссылка на отзыв.
+	LinkTTL time.Duration `yaml:"link_ttl" env-default:"336h"`
+}
+
+// Webhooks - настройки доставки событий броней во внешние системы.
+type Webhooks struct {
+	PollInterval time.Duration `yaml:"poll_interval" env-default:"2s"`
+	Timeout      time.Duration `yaml:"timeout" env-default:"5s"`
+	// MaxAttempts - сколько раз пытаться доставить событие, прежде чем считать доставку неудачной.
+	MaxAttempts int           `yaml:"max_attempts" env-default:"8"`
+	BaseBackoff time.Duration `yaml:"base_backoff" env-default:"30s"`
+	MaxBackoff  time.Duration `yaml:"max_backoff" env-default:"1h"`
+	// DisableAfter - после стольких неудачных доставок подряд подписка отключается.
+	DisableAfter int `yaml:"disable_after" env-default:"5"`
+}
+
 func MustLoad(configPath string) *Config {
 	// проверка существования файла
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
@@ -63,8 +218,14 @@ func MustLoad(configPath string) *Config {
 	var cfg Config
 
 	if err := cleanenv.ReadConfig(configPath, &cfg); err != nil {
-		log.Fatalf("cannot read config: %s", configPath)
+		log.Fatalf("cannot read config %s: %s", configPath, err)
+	}
+
+	location, err := time.LoadLocation(cfg.Restaurant.Timezone)
+	if err != nil {
+		log.Fatalf("invalid restaurant timezone %q: %s", cfg.Restaurant.Timezone, err)
 	}
+	cfg.Restaurant.Location = location
 
 	return &cfg
 }
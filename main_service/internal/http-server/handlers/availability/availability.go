@@ -0,0 +1,63 @@
+package availability
+
+import (
+	"log/slog"
+	tablesrv "main_service/internal/http-server/handlers/middleware/tables"
+	resp "main_service/internal/lib/api/response"
+	"main_service/internal/lib/logger/sl"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+)
+
+// defaultPartySize - размер компании, для которого считается длительность визита, если partySize не указан.
+const defaultPartySize = 2
+
+// New возвращает состояние каждого стола для брони на время из параметра at (RFC 3339):
+// свободен, занят, заблокирован или недоступен из-за загрузки кухни. Необязательный параметр partySize
+// влияет на длительность визита и проверку загрузки кухни.
+func New(log *slog.Logger, tableService *tablesrv.TableService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.availability.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		at, err := time.Parse(time.RFC3339, r.URL.Query().Get("at"))
+		if err != nil {
+			log.Warn("invalid time", slog.String("at", r.URL.Query().Get("at")))
+
+			render.JSON(w, r, resp.Error("Field at must be a time in RFC 3339 format"))
+
+			return
+		}
+
+		partySize := defaultPartySize
+		if raw := r.URL.Query().Get("partySize"); raw != "" {
+			partySize, err = strconv.Atoi(raw)
+			if err != nil || partySize <= 0 || partySize > 50 {
+				log.Warn("invalid party size", slog.String("partySize", raw))
+
+				render.JSON(w, r, resp.Error("Field partySize must be a number from 1 to 50"))
+
+				return
+			}
+		}
+
+		availability, err := tableService.Availability(r.Context(), at, int16(partySize))
+		if err != nil {
+			log.Error("failed to get availability", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to fetch availability"))
+
+			return
+		}
+
+		render.JSON(w, r, resp.OKWithData(availability))
+	}
+}
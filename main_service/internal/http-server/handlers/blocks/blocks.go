@@ -0,0 +1,190 @@
+package blocks
+
+import (
+	"errors"
+	"log/slog"
+	"main_service/internal/clients/sso/grpc"
+	tablesrv "main_service/internal/http-server/handlers/middleware/tables"
+	"main_service/internal/lib/api/auth"
+	resp "main_service/internal/lib/api/response"
+	"main_service/internal/lib/logger/sl"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator"
+)
+
+// CreateRequest - блокировка столов. Нужно указать либо tableIds, либо zone.
+type CreateRequest struct {
+	TableIDs []int16   `json:"tableIds" validate:"required_without=Zone,omitempty,max=100,unique,dive,gt=0"`
+	Zone     string    `json:"zone" validate:"max=50"`
+	StartsAt time.Time `json:"startsAt" validate:"required"`
+	EndsAt   time.Time `json:"endsAt" validate:"required"`
+	Reason   string    `json:"reason" validate:"max=200"`
+	// ListConflicts - вернуть брони, которые уже пересекаются с новой блокировкой.
+	ListConflicts bool `json:"listConflicts"`
+}
+
+type CreateResponse struct {
+	Block     models.TableBlock    `json:"block"`
+	Conflicts []models.BookingInfo `json:"conflicts,omitempty"`
+}
+
+// Create блокирует столы или зал на период. Доступно только админам.
+func Create(log *slog.Logger, authClient *grpc.Client, tableService *tablesrv.TableService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.blocks.Create"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		adminID, ok := auth.RequireAdmin(log, authClient, w, r)
+		if !ok {
+			return
+		}
+
+		var req CreateRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to decode request"))
+
+			return
+		}
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			log.Error("invalid request", sl.Err(err))
+
+			render.JSON(w, r, resp.ValidationError(validateErr))
+
+			return
+		}
+
+		if len(req.TableIDs) > 0 && req.Zone != "" {
+			render.JSON(w, r, resp.Error("Specify either tableIds or zone, not both"))
+
+			return
+		}
+
+		if !req.EndsAt.After(req.StartsAt) {
+			render.JSON(w, r, resp.Error("Field EndsAt must be after StartsAt"))
+
+			return
+		}
+
+		block, conflicts, err := tableService.CreateBlock(r.Context(), models.TableBlock{
+			TableIDs:  req.TableIDs,
+			Zone:      req.Zone,
+			StartsAt:  req.StartsAt,
+			EndsAt:    req.EndsAt,
+			Reason:    strings.TrimSpace(req.Reason),
+			CreatedBy: adminID,
+		}, req.ListConflicts)
+		if err != nil {
+			if errors.Is(err, storage.ErrZoneNotFound) {
+				log.Warn("failed to create block, zone not found", slog.String("zone", req.Zone))
+
+				render.JSON(w, r, resp.Error("Zone not found"))
+
+				return
+			}
+
+			log.Error("failed to create block", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to create table block"))
+
+			return
+		}
+
+		log.Info("table block created",
+			slog.Int64("blockID", block.ID),
+			slog.Int("conflicts", len(conflicts)),
+		)
+
+		render.JSON(w, r, resp.OKWithData(CreateResponse{
+			Block:     block,
+			Conflicts: conflicts,
+		}))
+	}
+}
+
+// List возвращает текущие и будущие блокировки столов. Доступно только админам.
+func List(log *slog.Logger, authClient *grpc.Client, tableService *tablesrv.TableService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.blocks.List"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		if _, ok := auth.RequireAdmin(log, authClient, w, r); !ok {
+			return
+		}
+
+		blocks, err := tableService.GetBlocks(r.Context())
+		if err != nil {
+			log.Error("failed to get blocks", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to fetch table blocks"))
+
+			return
+		}
+
+		render.JSON(w, r, resp.OKWithData(blocks))
+	}
+}
+
+// Delete снимает блокировку столов. Доступно только админам.
+func Delete(log *slog.Logger, authClient *grpc.Client, tableService *tablesrv.TableService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.blocks.Delete"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		if _, ok := auth.RequireAdmin(log, authClient, w, r); !ok {
+			return
+		}
+
+		id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil || id <= 0 {
+			log.Warn("invalid block id", slog.String("id", chi.URLParam(r, "id")))
+
+			render.JSON(w, r, resp.Error("Invalid block id"))
+
+			return
+		}
+
+		if err := tableService.DeleteBlock(r.Context(), id); err != nil {
+			if errors.Is(err, storage.ErrBlockNotFound) {
+				render.JSON(w, r, resp.Error("Table block not found"))
+
+				return
+			}
+
+			log.Error("failed to delete block", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to delete table block"))
+
+			return
+		}
+
+		log.Info("table block deleted", slog.Int64("blockID", id))
+
+		render.JSON(w, r, resp.OK())
+	}
+}
@@ -0,0 +1,190 @@
+package bookseries
+
+import (
+	"errors"
+	"log/slog"
+	"main_service/internal/clients/sso/grpc"
+	booktable "main_service/internal/http-server/handlers/book_table"
+	bookingsrv "main_service/internal/http-server/handlers/middleware/booking"
+	resp "main_service/internal/lib/api/response"
+	"main_service/internal/lib/logger/sl"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator"
+)
+
+const dateLayout = "2006-01-02"
+
+// intervals - через сколько недель повторяется бронь для каждой частоты.
+var intervals = map[string]int{
+	"weekly":   1,
+	"biweekly": 2,
+}
+
+type Request struct {
+	booktable.Request
+	Frequency string `json:"frequency" validate:"required,oneof=weekly biweekly"`
+	// Until - последняя дата серии включительно в формате YYYY-MM-DD.
+	Until string `json:"until" validate:"omitempty,len=10"`
+	Count int    `json:"count" validate:"omitempty,gt=0,lte=52"`
+}
+
+// New создаёт повторяющуюся бронь стола. Нужно указать либо until, либо count.
+func New(log *slog.Logger, authClient *grpc.Client, bookingService *bookingsrv.BookingService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.book-series.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userID, ok := r.Context().Value(models.ContextKey("uid")).(float64)
+		if !ok || userID <= 0 {
+			log.Error("unauthorized: no userID in context")
+
+			render.JSON(w, r, resp.Error("Unauthorized"))
+
+			return
+		}
+
+		isAdmin, err := authClient.IsAdmin(r.Context(), int64(userID))
+		if err != nil {
+			log.Error("failed to check user role", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to check user role"))
+
+			return
+		}
+
+		if isAdmin {
+			log.Warn("admin tried to book a table", slog.Int("userID", int(userID)))
+
+			render.JSON(w, r, resp.Error("Admins cannot book tables"))
+
+			return
+		}
+
+		var req Request
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to decode request"))
+
+			return
+		}
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			log.Error("invalid request", sl.Err(err))
+
+			render.JSON(w, r, resp.ValidationError(validateErr))
+
+			return
+		}
+
+		if req.TableID == 0 && req.CombinationID == 0 {
+			render.JSON(w, r, resp.Error("Either tableId or combinationId must be specified"))
+
+			return
+		}
+
+		if req.PromoCode != "" {
+			render.JSON(w, r, resp.Error("Promo codes cannot be applied to recurring bookings"))
+
+			return
+		}
+
+		if len(req.PreOrder) > 0 {
+			render.JSON(w, r, resp.Error("Dishes cannot be pre-ordered for recurring bookings"))
+
+			return
+		}
+
+		if (req.Until == "") == (req.Count == 0) {
+			render.JSON(w, r, resp.Error("Either until or count must be specified"))
+
+			return
+		}
+
+		var until time.Time
+		if req.Until != "" {
+			until, err = time.Parse(dateLayout, req.Until)
+			if err != nil {
+				render.JSON(w, r, resp.Error("Field Until must be a date in YYYY-MM-DD format"))
+
+				return
+			}
+
+			// Последний день включается в серию.
+			until = until.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		}
+
+		if time.Until(req.BookingAt) < booktable.MinAdvance {
+			log.Warn("booking too close to current time", slog.Int("userID", int(userID)))
+
+			render.JSON(w, r, resp.Error("You can only book at least 5 hours in advance"))
+
+			return
+		}
+
+		interval := intervals[req.Frequency]
+
+		times := bookingsrv.SeriesOccurrences(req.BookingAt, interval, req.Count, until)
+		if len(times) < 2 {
+			render.JSON(w, r, resp.Error("Recurring booking must have at least 2 dates"))
+
+			return
+		}
+
+		result, err := bookingService.BookSeries(r.Context(), req.Booking(int64(userID)), interval, times)
+		if err != nil {
+			if errors.Is(err, storage.ErrUserBlocked) {
+				log.Warn("failed to book series, user is blocked", slog.Int("userID", int(userID)))
+
+				render.JSON(w, r, resp.Error("Booking is not available for this account, please contact the restaurant"))
+
+				return
+			} else if errors.Is(err, storage.ErrCombinationNotFound) {
+				log.Warn("failed to book series, combination not found", slog.Int("combinationID", req.CombinationID))
+
+				render.JSON(w, r, resp.Error("Table combination not found"))
+
+				return
+			} else if errors.Is(err, storage.ErrPartyTooLarge) {
+				log.Warn("failed to book series, party is too large", slog.Int("partySize", req.PartySize))
+
+				render.JSON(w, r, resp.Error("Party is too large for these tables"))
+
+				return
+			} else if errors.Is(err, storage.ErrAttributesMismatch) {
+				log.Warn("failed to book series, tables lack required attributes", slog.Any("attributes", req.RequiredAttributes))
+
+				render.JSON(w, r, resp.Error("Tables do not have the required attributes"))
+
+				return
+			}
+
+			log.Error("failed to book series", sl.Err(err), slog.Int64("seriesID", result.SeriesID))
+
+			render.JSON(w, r, resp.Error("Failed to book recurring booking"))
+
+			return
+		}
+
+		log.Info("series booked",
+			slog.Int("userID", int(userID)),
+			slog.Int64("seriesID", result.SeriesID),
+			slog.Int("booked", len(result.Booked)),
+			slog.Int("conflicts", len(result.Conflicts)),
+		)
+
+		render.JSON(w, r, resp.OKWithData(result))
+	}
+}
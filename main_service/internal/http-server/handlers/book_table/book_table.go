@@ -10,7 +10,7 @@ import (
 	"main_service/internal/models"
 	"main_service/internal/storage"
 	"net/http"
-
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/middleware"
@@ -18,14 +18,59 @@ import (
 	"github.com/go-playground/validator"
 )
 
+const defaultPartySize = 2
+
+// MinAdvance - за сколько минимум до визита можно забронировать стол.
+const MinAdvance = 5 * time.Hour
+
+// Request - запрос на бронь. Вместо TableID можно указать CombinationID,
+// чтобы забронировать комбинацию столов для большой компании. Если не указано ни то, ни другое,
+// стол подбирается с учётом обязательных и желательных атрибутов.
 type Request struct {
-	TableID   int       `json:"tableId" validate:"required,gt=0"`
-	BookingAt time.Time `json:"bookingAt" validate:"required"`
+	TableID       int       `json:"tableId" validate:"omitempty,gt=0"`
+	CombinationID int       `json:"combinationId" validate:"omitempty,gt=0"`
+	BookingAt     time.Time `json:"bookingAt" validate:"required"`
+	PartySize     int       `json:"partySize" validate:"omitempty,gt=0,lte=50"`
+	Notes         string    `json:"notes" validate:"max=500"`
+	Occasion      string    `json:"occasion" validate:"omitempty,oneof=birthday anniversary business date celebration other"`
+	Allergens     []string  `json:"allergens" validate:"max=14,dive,oneof=gluten crustaceans eggs fish peanuts soy milk nuts celery mustard sesame sulphites lupin molluscs"`
+	// RequiredAttributes - атрибуты, которые обязательно должны быть у стола.
+	RequiredAttributes []string `json:"requiredAttributes" validate:"omitempty,max=5,unique,dive,oneof=window terrace booth quiet wheelchair_accessible"`
+	// PreferredAttributes - желательные атрибуты: при подборе стола выбирается тот, у которого их больше.
+	PreferredAttributes []string `json:"preferredAttributes" validate:"omitempty,max=5,unique,dive,oneof=window terrace booth quiet wheelchair_accessible"`
+	// PromoCode - промокод или код подарочного сертификата, регистр не важен.
+	PromoCode string `json:"promoCode" validate:"max=32"`
+	// PreOrder - блюда из меню, которые гость заказывает заранее.
+	PreOrder []PreOrderItem `json:"preOrder" validate:"max=30,dive"`
+}
+
+// PreOrderItem - блюдо меню и количество порций в предзаказе.
+type PreOrderItem struct {
+	DishID   int64 `json:"dishId" validate:"required,gt=0"`
+	Quantity int   `json:"quantity" validate:"required,gt=0,lte=100"`
 }
 
 type Response struct {
 	resp.Response
-	Status string `json:"status"`
+	Status    string `json:"status"`
+	BookingID int64  `json:"booking_id"`
+	// BookingStatus - confirmed, pending_approval, если бронь ждёт подтверждения админа,
+	// или pending_payment, если бронь ждёт оплаты депозита.
+	BookingStatus string `json:"booking_status"`
+	TableID       int16  `json:"table_id"`
+	// Attributes - атрибуты выбранного места.
+	Attributes []string `json:"attributes"`
+	// PromoCode - применённый промокод и что он даёт.
+	PromoCode        string `json:"promo_code,omitempty"`
+	PromoDescription string `json:"promo_description,omitempty"`
+	// PreOrder - предзаказ с названиями и ценами блюд.
+	PreOrder []models.PreOrderItem `json:"pre_order,omitempty"`
+	// PaymentURL, DepositAmount, DepositCurrency и PaymentExpiresAt заполнены, если бронь ждёт оплаты депозита.
+	// Неоплаченная к PaymentExpiresAt бронь отменяется.
+	PaymentURL       string     `json:"payment_url,omitempty"`
+	DepositAmount    int64      `json:"deposit_amount,omitempty"`
+	DepositCurrency  string     `json:"deposit_currency,omitempty"`
+	PaymentExpiresAt *time.Time `json:"payment_expires_at,omitempty"`
 }
 
 func New(log *slog.Logger, authClient *grpc.Client, bookingService *bookingsrv.BookingService) http.HandlerFunc {
@@ -85,7 +130,7 @@ func New(log *slog.Logger, authClient *grpc.Client, bookingService *bookingsrv.B
 			return
 		}
 
-		if time.Until(req.BookingAt) < 5*time.Hour {
+		if time.Until(req.BookingAt) < MinAdvance {
 			log.Warn("booking too close to current time", slog.Int("userID", int(userID)))
 
 			render.JSON(w, r, resp.Error("You can only book at least 5 hours in advance"))
@@ -93,18 +138,45 @@ func New(log *slog.Logger, authClient *grpc.Client, bookingService *bookingsrv.B
 			return
 		}
 
-		booking := models.Booking{
-			UserID:      int64(userID),
-			TableID:     int16(req.TableID),
-			BookingTime: req.BookingAt,
-		}
-
-		err = bookingService.BookTable(r.Context(), booking)
+		booking, err := bookingService.BookTable(r.Context(), req.Booking(int64(userID)))
 		if err != nil {
 			if errors.Is(err, storage.ErrTableIsBooked) {
 				log.Warn("failed to book table, table is already booked")
 
-				render.JSON(w, r, resp.Error("Table is already booked"))
+				alternatives, err := bookingService.SuggestAlternatives(r.Context(), req.Booking(int64(userID)), time.Now().Add(MinAdvance))
+				if err != nil {
+					log.Error("failed to suggest alternatives", sl.Err(err))
+
+					render.JSON(w, r, resp.Error("Table is already booked"))
+
+					return
+				}
+
+				render.JSON(w, r, resp.ErrorWithData("Table is already booked", alternatives))
+
+				return
+			} else if errors.Is(err, storage.ErrTableIsBlocked) {
+				log.Warn("failed to book table, table is blocked")
+
+				render.JSON(w, r, resp.Error("Table is not available at this time"))
+
+				return
+			} else if errors.Is(err, storage.ErrPacingLimit) {
+				log.Warn("failed to book table, kitchen pacing limit reached")
+
+				render.JSON(w, r, resp.Error("Too many guests arrive at this time, please choose another time"))
+
+				return
+			} else if errors.Is(err, storage.ErrZoneClosed) {
+				log.Warn("failed to book table, zone is closed")
+
+				render.JSON(w, r, resp.Error("This area is closed at this time"))
+
+				return
+			} else if errors.Is(err, storage.ErrUserBlocked) {
+				log.Warn("failed to book table, user is blocked", slog.Int("userID", int(userID)))
+
+				render.JSON(w, r, resp.Error("Booking is not available for this account, please contact the restaurant"))
 
 				return
 			} else if errors.Is(err, storage.ErrUserAlreadyBooked) {
@@ -112,6 +184,75 @@ func New(log *slog.Logger, authClient *grpc.Client, bookingService *bookingsrv.B
 
 				render.JSON(w, r, resp.Error("user can't book more then 1 table"))
 
+				return
+			} else if errors.Is(err, storage.ErrCombinationNotFound) {
+				log.Warn("failed to book table, combination not found", slog.Int("combinationID", req.CombinationID))
+
+				render.JSON(w, r, resp.Error("Table combination not found"))
+
+				return
+			} else if errors.Is(err, storage.ErrPartyTooLarge) {
+				log.Warn("failed to book table, party is too large", slog.Int("partySize", req.PartySize))
+
+				render.JSON(w, r, resp.Error("Party is too large for these tables"))
+
+				return
+			} else if errors.Is(err, storage.ErrPromoCodeNotFound) {
+				log.Warn("failed to book table, promo code not found", slog.String("promoCode", req.PromoCode))
+
+				render.JSON(w, r, resp.Error("Promo code not found"))
+
+				return
+			} else if errors.Is(err, storage.ErrPromoCodeInvalid) {
+				log.Warn("failed to book table, promo code is not valid", slog.String("promoCode", req.PromoCode))
+
+				render.JSON(w, r, resp.Error("Promo code is not valid for this date"))
+
+				return
+			} else if errors.Is(err, storage.ErrPromoCodeExhausted) {
+				log.Warn("failed to book table, promo code usage limit reached", slog.String("promoCode", req.PromoCode))
+
+				render.JSON(w, r, resp.Error("Promo code has already been used up"))
+
+				return
+			} else if errors.Is(err, storage.ErrDishNotFound) {
+				log.Warn("failed to book table, pre-ordered dish not found")
+
+				render.JSON(w, r, resp.Error("Pre-ordered dish not found in the menu"))
+
+				return
+			} else if errors.Is(err, storage.ErrDishUnavailable) {
+				log.Warn("failed to book table, pre-ordered dish is unavailable")
+
+				render.JSON(w, r, resp.Error("Pre-ordered dish is not available"))
+
+				return
+			} else if errors.Is(err, storage.ErrPreOrderTooLarge) {
+				log.Warn("failed to book table, pre-order is too large", slog.Int("partySize", req.PartySize))
+
+				render.JSON(w, r, resp.Error("Too many dishes pre-ordered for this party size"))
+
+				return
+			} else if errors.Is(err, storage.ErrAttributesMismatch) {
+				log.Warn("failed to book table, table lacks required attributes", slog.Any("attributes", req.RequiredAttributes))
+
+				render.JSON(w, r, resp.Error("Table does not have the required attributes"))
+
+				return
+			} else if errors.Is(err, storage.ErrNoSuitableTable) {
+				log.Warn("failed to book table, no suitable table", slog.Any("attributes", req.RequiredAttributes))
+
+				alternatives, err := bookingService.SuggestAlternatives(r.Context(), req.Booking(int64(userID)), time.Now().Add(MinAdvance))
+				if err != nil {
+					log.Error("failed to suggest alternatives", sl.Err(err))
+
+					render.JSON(w, r, resp.Error("No suitable table available"))
+
+					return
+				}
+
+				render.JSON(w, r, resp.ErrorWithData("No suitable table available", alternatives))
+
 				return
 			}
 
@@ -122,15 +263,80 @@ func New(log *slog.Logger, authClient *grpc.Client, bookingService *bookingsrv.B
 			return
 		}
 
-		log.Info("table booked successfully", slog.Int("userID", int(userID)))
+		log.Info("table booked successfully",
+			slog.Int("userID", int(userID)),
+			slog.Int64("bookingID", booking.ID),
+			slog.String("bookingStatus", booking.Status),
+			slog.Int("tableID", int(booking.TableID)),
+		)
+
+		ResponseOK(w, r, booking)
+	}
+}
+
+// Booking собирает бронь пользователя userID из запроса.
+func (req Request) Booking(userID int64) models.Booking {
+	partySize := req.PartySize
+	if partySize == 0 {
+		partySize = defaultPartySize
+	}
+
+	return models.Booking{
+		UserID:        userID,
+		TableID:       int16(req.TableID),
+		CombinationID: req.CombinationID,
+		BookingTime:   req.BookingAt,
+		PartySize:     int16(partySize),
+		Notes:         strings.TrimSpace(req.Notes),
+		Occasion:      req.Occasion,
+		Allergens:     req.Allergens,
+		PromoCode:     req.PromoCode,
+		PreOrder:      PreOrderItems(req.PreOrder),
+
+		RequiredAttributes:  req.RequiredAttributes,
+		PreferredAttributes: req.PreferredAttributes,
+	}
+}
 
-		ResponseOK(w, r)
+// PreOrderItems переводит блюда предзаказа из запроса в модель. Названия и цены заполняет сервис по меню.
+func PreOrderItems(items []PreOrderItem) []models.PreOrderItem {
+	if len(items) == 0 {
+		return nil
 	}
+
+	result := make([]models.PreOrderItem, 0, len(items))
+	for _, item := range items {
+		result = append(result, models.PreOrderItem{DishID: item.DishID, Quantity: item.Quantity})
+	}
+
+	return result
 }
 
-func ResponseOK(w http.ResponseWriter, r *http.Request) {
-	render.JSON(w, r, Response{
-		Response: resp.OK(),
-		Status:   "ok",
-	})
+func ResponseOK(w http.ResponseWriter, r *http.Request, booking models.Booking) {
+	attributes := booking.Attributes
+	if attributes == nil {
+		attributes = []string{}
+	}
+
+	response := Response{
+		Response:      resp.OK(),
+		Status:        "ok",
+		BookingID:     booking.ID,
+		BookingStatus: booking.Status,
+		TableID:       booking.TableID,
+		Attributes:    attributes,
+
+		PromoCode:        booking.PromoCode,
+		PromoDescription: booking.PromoDescription,
+		PreOrder:         booking.PreOrder,
+	}
+
+	if booking.Deposit != nil {
+		response.PaymentURL = booking.Deposit.PaymentURL
+		response.DepositAmount = booking.Deposit.Amount
+		response.DepositCurrency = booking.Deposit.Currency
+		response.PaymentExpiresAt = &booking.Deposit.ExpiresAt
+	}
+
+	render.JSON(w, r, response)
 }
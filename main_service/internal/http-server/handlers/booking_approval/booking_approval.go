@@ -0,0 +1,138 @@
+package bookingapproval
+
+import (
+	"errors"
+	"log/slog"
+	"main_service/internal/clients/sso/grpc"
+	bookingsrv "main_service/internal/http-server/handlers/middleware/booking"
+	"main_service/internal/lib/api/auth"
+	resp "main_service/internal/lib/api/response"
+	"main_service/internal/lib/logger/sl"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator"
+)
+
+type RejectRequest struct {
+	Reason string `json:"reason" validate:"max=500"`
+}
+
+// Approve подтверждает бронь, ожидающую решения админа. Доступно только админам.
+func Approve(log *slog.Logger, authClient *grpc.Client, bookingService *bookingsrv.BookingService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.booking-approval.Approve"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		actor, bookingID, ok := parseRequest(log, authClient, w, r)
+		if !ok {
+			return
+		}
+
+		if err := bookingService.ApproveBooking(r.Context(), bookingID, actor); err != nil {
+			renderError(log, w, r, err, bookingID)
+
+			return
+		}
+
+		log.Info("booking approved", slog.Int64("bookingID", bookingID))
+
+		render.JSON(w, r, resp.OK())
+	}
+}
+
+// Reject отклоняет бронь, ожидающую решения админа, и освобождает стол. Доступно только админам.
+func Reject(log *slog.Logger, authClient *grpc.Client, bookingService *bookingsrv.BookingService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.booking-approval.Reject"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		actor, bookingID, ok := parseRequest(log, authClient, w, r)
+		if !ok {
+			return
+		}
+
+		var req RejectRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to decode request"))
+
+			return
+		}
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			log.Error("invalid request", sl.Err(err))
+
+			render.JSON(w, r, resp.ValidationError(validateErr))
+
+			return
+		}
+
+		err := bookingService.RejectBooking(r.Context(), bookingID, strings.TrimSpace(req.Reason), actor)
+		if err != nil {
+			renderError(log, w, r, err, bookingID)
+
+			return
+		}
+
+		log.Info("booking rejected", slog.Int64("bookingID", bookingID))
+
+		render.JSON(w, r, resp.OK())
+	}
+}
+
+// parseRequest проверяет, что запрос сделал админ, и читает id брони из пути. При ошибке ответ уже записан.
+func parseRequest(log *slog.Logger, authClient *grpc.Client, w http.ResponseWriter, r *http.Request) (models.Actor, int64, bool) {
+	adminID, ok := auth.RequireAdmin(log, authClient, w, r)
+	if !ok {
+		return models.Actor{}, 0, false
+	}
+
+	bookingID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil || bookingID <= 0 {
+		log.Warn("invalid booking id", slog.String("id", chi.URLParam(r, "id")))
+
+		render.JSON(w, r, resp.Error("Invalid booking id"))
+
+		return models.Actor{}, 0, false
+	}
+
+	return models.Actor{ID: adminID, Role: models.RoleAdmin}, bookingID, true
+}
+
+func renderError(log *slog.Logger, w http.ResponseWriter, r *http.Request, err error, bookingID int64) {
+	if errors.Is(err, storage.ErrBookingNotFound) {
+		log.Warn("booking not found", slog.Int64("bookingID", bookingID))
+
+		render.JSON(w, r, resp.Error("Booking not found"))
+
+		return
+	} else if errors.Is(err, storage.ErrUnexpectedStatus) {
+		log.Warn("booking is not pending approval", slog.Int64("bookingID", bookingID))
+
+		render.JSON(w, r, resp.Error("Booking is not pending approval"))
+
+		return
+	}
+
+	log.Error("failed to review booking", sl.Err(err), slog.Int64("bookingID", bookingID))
+
+	render.JSON(w, r, resp.Error("Failed to review booking"))
+}
@@ -0,0 +1,66 @@
+package bookinghistory
+
+import (
+	"errors"
+	"log/slog"
+	"main_service/internal/clients/sso/grpc"
+	bookingsrv "main_service/internal/http-server/handlers/middleware/booking"
+	"main_service/internal/lib/api/auth"
+	resp "main_service/internal/lib/api/response"
+	"main_service/internal/lib/logger/sl"
+	"main_service/internal/storage"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+)
+
+func New(log *slog.Logger, authClient *grpc.Client, bookingService *bookingsrv.BookingService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.booking-history.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		if _, ok := auth.RequireAdmin(log, authClient, w, r); !ok {
+			return
+		}
+
+		bookingID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil || bookingID <= 0 {
+			log.Warn("invalid booking id", slog.String("id", chi.URLParam(r, "id")))
+
+			render.JSON(w, r, resp.Error("Invalid booking id"))
+
+			return
+		}
+
+		events, err := bookingService.GetBookingHistory(r.Context(), bookingID)
+		if err != nil {
+			if errors.Is(err, storage.ErrBookingNotFound) {
+				log.Warn("booking not found", slog.Int64("bookingID", bookingID))
+
+				render.JSON(w, r, resp.Error("Booking not found"))
+
+				return
+			}
+
+			log.Error("failed to get booking history", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to fetch booking history"))
+
+			return
+		}
+
+		log.Info("booking history fetched successfully",
+			slog.Int64("bookingID", bookingID),
+			slog.Int("count", len(events)),
+		)
+
+		render.JSON(w, r, resp.OKWithData(events))
+	}
+}
@@ -0,0 +1,101 @@
+package bookingstatus
+
+import (
+	"errors"
+	"log/slog"
+	"main_service/internal/clients/sso/grpc"
+	bookingsrv "main_service/internal/http-server/handlers/middleware/booking"
+	"main_service/internal/lib/api/auth"
+	resp "main_service/internal/lib/api/response"
+	"main_service/internal/lib/logger/sl"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator"
+)
+
+type Request struct {
+	Status string `json:"status" validate:"required,oneof=completed no_show"`
+}
+
+// New закрывает бронь с итогом визита. Доступно только админам.
+func New(log *slog.Logger, authClient *grpc.Client, bookingService *bookingsrv.BookingService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.booking-status.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		adminID, ok := auth.RequireAdmin(log, authClient, w, r)
+		if !ok {
+			return
+		}
+
+		bookingID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil || bookingID <= 0 {
+			log.Warn("invalid booking id", slog.String("id", chi.URLParam(r, "id")))
+
+			render.JSON(w, r, resp.Error("Invalid booking id"))
+
+			return
+		}
+
+		var req Request
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to decode request"))
+
+			return
+		}
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			log.Error("invalid request", sl.Err(err))
+
+			render.JSON(w, r, resp.ValidationError(validateErr))
+
+			return
+		}
+
+		actor := models.Actor{ID: adminID, Role: models.RoleAdmin}
+
+		err = bookingService.CloseBooking(r.Context(), log, bookingID, req.Status, actor)
+		if err != nil {
+			if errors.Is(err, storage.ErrBookingNotFound) {
+				log.Warn("booking not found", slog.Int64("bookingID", bookingID))
+
+				render.JSON(w, r, resp.Error("Booking not found"))
+
+				return
+			} else if errors.Is(err, storage.ErrUnexpectedStatus) {
+				log.Warn("booking is not confirmed", slog.Int64("bookingID", bookingID))
+
+				render.JSON(w, r, resp.Error("Only active confirmed bookings can be closed"))
+
+				return
+			}
+
+			log.Error("failed to change booking status", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to change booking status"))
+
+			return
+		}
+
+		log.Info("booking status changed",
+			slog.Int64("bookingID", bookingID),
+			slog.String("status", req.Status),
+		)
+
+		render.JSON(w, r, resp.OK())
+	}
+}
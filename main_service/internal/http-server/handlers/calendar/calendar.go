@@ -0,0 +1,204 @@
+package calendar
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"main_service/internal/clients/sso/grpc"
+	calendarsrv "main_service/internal/http-server/handlers/middleware/calendar"
+	"main_service/internal/lib/api/auth"
+	resp "main_service/internal/lib/api/response"
+	"main_service/internal/lib/ical"
+	"main_service/internal/lib/logger/sl"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+)
+
+type TokenResponse struct {
+	FeedURL string `json:"feed_url"`
+}
+
+// IssueToken выдаёт админу новую секретную ссылку на календарную ленту броней.
+// Предыдущая ссылка этого админа перестаёт работать.
+func IssueToken(
+	log *slog.Logger,
+	authClient *grpc.Client,
+	calendarService *calendarsrv.CalendarService,
+	publicURL string,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.calendar.IssueToken"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		adminID, ok := auth.RequireAdmin(log, authClient, w, r)
+		if !ok {
+			return
+		}
+
+		token, err := calendarService.IssueToken(r.Context(), adminID)
+		if err != nil {
+			log.Error("failed to issue calendar token", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to issue calendar token"))
+
+			return
+		}
+
+		log.Info("calendar token issued", slog.Int64("userID", adminID))
+
+		render.JSON(w, r, resp.OKWithData(TokenResponse{
+			FeedURL: fmt.Sprintf("%s/calendar/%s.ics", strings.TrimSuffix(publicURL, "/"), token),
+		}))
+	}
+}
+
+// Feed отдаёт календарную ленту активных броней по секретному токену.
+// Маршрут публичный: календарные приложения не умеют передавать JWT.
+func Feed(log *slog.Logger, authClient *grpc.Client, calendarService *calendarsrv.CalendarService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.calendar.Feed"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userID, err := calendarService.TokenOwner(r.Context(), chi.URLParam(r, "token"))
+		if err != nil {
+			if errors.Is(err, storage.ErrTokenNotFound) {
+				log.Warn("unknown calendar token")
+
+				http.Error(w, "not found", http.StatusNotFound)
+
+				return
+			}
+
+			log.Error("failed to check calendar token", sl.Err(err))
+
+			http.Error(w, "internal error", http.StatusInternalServerError)
+
+			return
+		}
+
+		// Ссылка перестаёт работать, если у владельца отобрали права админа.
+		isAdmin, err := authClient.IsAdmin(r.Context(), userID)
+		if err != nil {
+			log.Error("failed to check user role", sl.Err(err))
+
+			http.Error(w, "internal error", http.StatusInternalServerError)
+
+			return
+		}
+
+		if !isAdmin {
+			log.Warn("calendar token owner is not an admin", slog.Int64("userID", userID))
+
+			http.Error(w, "not found", http.StatusNotFound)
+
+			return
+		}
+
+		cal, err := calendarService.Feed(r.Context())
+		if err != nil {
+			log.Error("failed to build calendar feed", sl.Err(err))
+
+			http.Error(w, "internal error", http.StatusInternalServerError)
+
+			return
+		}
+
+		writeCalendar(log, w, cal, "")
+	}
+}
+
+// BookingICS отдаёт .ics файл одной брони. Скачать его может владелец брони или админ.
+func BookingICS(log *slog.Logger, authClient *grpc.Client, calendarService *calendarsrv.CalendarService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.calendar.BookingICS"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userID, ok := r.Context().Value(models.ContextKey("uid")).(float64)
+		if !ok || userID <= 0 {
+			log.Error("unauthorized: no userID in context")
+
+			render.JSON(w, r, resp.Error("Unauthorized"))
+
+			return
+		}
+
+		bookingID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil || bookingID <= 0 {
+			log.Warn("invalid booking id", slog.String("id", chi.URLParam(r, "id")))
+
+			render.JSON(w, r, resp.Error("Invalid booking id"))
+
+			return
+		}
+
+		cal, booking, err := calendarService.BookingCalendar(r.Context(), bookingID)
+		if err != nil {
+			if errors.Is(err, storage.ErrBookingNotFound) {
+				log.Warn("booking not found", slog.Int64("bookingID", bookingID))
+
+				render.JSON(w, r, resp.Error("Booking not found"))
+
+				return
+			}
+
+			log.Error("failed to get booking", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to get booking"))
+
+			return
+		}
+
+		if booking.UserID != int64(userID) {
+			isAdmin, err := authClient.IsAdmin(r.Context(), int64(userID))
+			if err != nil {
+				log.Error("failed to check user role", sl.Err(err))
+
+				render.JSON(w, r, resp.Error("Failed to check user role"))
+
+				return
+			}
+
+			if !isAdmin {
+				log.Warn("user tried to download not his booking", slog.Int("userID", int(userID)))
+
+				// Не раскрываем, что бронь с таким id существует.
+				render.JSON(w, r, resp.Error("Booking not found"))
+
+				return
+			}
+		}
+
+		writeCalendar(log, w, cal, fmt.Sprintf("booking-%d.ics", bookingID))
+	}
+}
+
+// writeCalendar отдаёт календарь клиенту. Если указан filename, он отдаётся как файл для скачивания.
+func writeCalendar(log *slog.Logger, w http.ResponseWriter, cal ical.Calendar, filename string) {
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	if filename != "" {
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	}
+
+	if err := ical.Write(w, cal); err != nil {
+		log.Error("failed to write calendar", sl.Err(err))
+	}
+}
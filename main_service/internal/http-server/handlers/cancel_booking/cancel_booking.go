@@ -10,7 +10,6 @@ import (
 	"main_service/internal/models"
 	"main_service/internal/storage"
 	"main_service/internal/storage/postgres"
-	"main_service/internal/storage/redis"
 	"net/http"
 	"time"
 
@@ -103,13 +102,12 @@ func New(
 			}
 		}
 
-		booking := redis.Booking{
-			TableID: int64(req.TableID),
-			UserID:  int64(userID),
-			Time:    req.BookingTime,
+		actor := models.Actor{ID: int64(userID), Role: models.RoleCustomer}
+		if isAdmin {
+			actor.Role = models.RoleAdmin
 		}
 
-		err = bookingService.CancelBooking(r.Context(), booking)
+		err = bookingService.CancelBooking(r.Context(), req.TableID, req.BookingTime, actor)
 		if err != nil {
 			if errors.Is(err, storage.ErrBookingNotFound) {
 				log.Warn("booking not found", slog.Int64("tableID", int64(req.TableID)), slog.Time("bookingTime", req.BookingTime))
@@ -0,0 +1,108 @@
+package cancelseries
+
+import (
+	"errors"
+	"log/slog"
+	"main_service/internal/clients/sso/grpc"
+	bookingsrv "main_service/internal/http-server/handlers/middleware/booking"
+	resp "main_service/internal/lib/api/response"
+	"main_service/internal/lib/logger/sl"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+)
+
+type Response struct {
+	Cancelled int `json:"cancelled"`
+}
+
+// New отменяет все будущие даты повторяющейся брони. Отдельную дату отменяют через /cancel.
+func New(log *slog.Logger, authClient *grpc.Client, bookingService *bookingsrv.BookingService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.cancel-series.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userID, ok := r.Context().Value(models.ContextKey("uid")).(float64)
+		if !ok || userID <= 0 {
+			log.Error("unauthorized: no userID in context")
+
+			render.JSON(w, r, resp.Error("Unauthorized"))
+
+			return
+		}
+
+		isAdmin, err := authClient.IsAdmin(r.Context(), int64(userID))
+		if err != nil {
+			log.Error("failed to check user role", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to check user role"))
+
+			return
+		}
+
+		seriesID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil || seriesID <= 0 {
+			log.Warn("invalid series id", slog.String("id", chi.URLParam(r, "id")))
+
+			render.JSON(w, r, resp.Error("Invalid series id"))
+
+			return
+		}
+
+		series, err := bookingService.GetSeries(r.Context(), seriesID)
+		if err != nil {
+			if errors.Is(err, storage.ErrSeriesNotFound) {
+				log.Warn("series not found", slog.Int64("seriesID", seriesID))
+
+				render.JSON(w, r, resp.Error("Series not found"))
+
+				return
+			}
+
+			log.Error("failed to get series", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to get series"))
+
+			return
+		}
+
+		if !isAdmin && series.UserID != int64(userID) {
+			log.Warn("user tried to cancel not his series", slog.Int("userID", int(userID)))
+
+			render.JSON(w, r, resp.Error("You can cancel only your own bookings"))
+
+			return
+		}
+
+		actor := models.Actor{ID: int64(userID), Role: models.RoleCustomer}
+		if isAdmin {
+			actor.Role = models.RoleAdmin
+		}
+
+		cancelled, err := bookingService.CancelSeries(r.Context(), seriesID, actor)
+		if err != nil {
+			log.Error("failed to cancel series", sl.Err(err), slog.Int("cancelled", cancelled))
+
+			render.JSON(w, r, resp.Error("Failed to cancel series"))
+
+			return
+		}
+
+		log.Info("series canceled successfully",
+			slog.Int("userID", int(userID)),
+			slog.Int64("seriesID", seriesID),
+			slog.Int("cancelled", cancelled),
+		)
+
+		render.JSON(w, r, resp.OKWithData(Response{Cancelled: cancelled}))
+	}
+}
@@ -0,0 +1,73 @@
+package checkin
+
+import (
+	"errors"
+	"log/slog"
+	"main_service/internal/clients/sso/grpc"
+	bookingsrv "main_service/internal/http-server/handlers/middleware/booking"
+	"main_service/internal/lib/api/auth"
+	resp "main_service/internal/lib/api/response"
+	"main_service/internal/lib/logger/sl"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+)
+
+// New отмечает, что гости пришли и сели за стол. Доступно только админам.
+func New(log *slog.Logger, authClient *grpc.Client, bookingService *bookingsrv.BookingService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.check-in.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		adminID, ok := auth.RequireAdmin(log, authClient, w, r)
+		if !ok {
+			return
+		}
+
+		bookingID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil || bookingID <= 0 {
+			log.Warn("invalid booking id", slog.String("id", chi.URLParam(r, "id")))
+
+			render.JSON(w, r, resp.Error("Invalid booking id"))
+
+			return
+		}
+
+		actor := models.Actor{ID: adminID, Role: models.RoleAdmin}
+
+		if err := bookingService.CheckIn(r.Context(), bookingID, actor); err != nil {
+			if errors.Is(err, storage.ErrBookingNotFound) {
+				log.Warn("booking not found", slog.Int64("bookingID", bookingID))
+
+				render.JSON(w, r, resp.Error("Booking not found"))
+
+				return
+			} else if errors.Is(err, storage.ErrUnexpectedStatus) {
+				log.Warn("booking cannot be checked in", slog.Int64("bookingID", bookingID))
+
+				render.JSON(w, r, resp.Error("Only active confirmed bookings can be checked in once"))
+
+				return
+			}
+
+			log.Error("failed to check in booking", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to check in booking"))
+
+			return
+		}
+
+		log.Info("booking checked in", slog.Int64("bookingID", bookingID))
+
+		render.JSON(w, r, resp.OK())
+	}
+}
@@ -0,0 +1,144 @@
+package combinations
+
+import (
+	"errors"
+	"log/slog"
+	"main_service/internal/clients/sso/grpc"
+	tablesrv "main_service/internal/http-server/handlers/middleware/tables"
+	"main_service/internal/lib/api/auth"
+	resp "main_service/internal/lib/api/response"
+	"main_service/internal/lib/logger/sl"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator"
+)
+
+type CreateRequest struct {
+	Name     string  `json:"name" validate:"required,max=100"`
+	TableIDs []int16 `json:"tableIds" validate:"required,min=2,max=10,unique,dive,gt=0"`
+	Capacity int16   `json:"capacity" validate:"required,gt=0,lte=100"`
+}
+
+// Create добавляет комбинацию столов. Доступно только админам.
+func Create(log *slog.Logger, authClient *grpc.Client, tableService *tablesrv.TableService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.combinations.Create"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		if _, ok := auth.RequireAdmin(log, authClient, w, r); !ok {
+			return
+		}
+
+		var req CreateRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to decode request"))
+
+			return
+		}
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			log.Error("invalid request", sl.Err(err))
+
+			render.JSON(w, r, resp.ValidationError(validateErr))
+
+			return
+		}
+
+		combination, err := tableService.CreateCombination(r.Context(), models.TableCombination{
+			Name:     req.Name,
+			TableIDs: req.TableIDs,
+			Capacity: req.Capacity,
+		})
+		if err != nil {
+			log.Error("failed to create combination", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to create table combination"))
+
+			return
+		}
+
+		log.Info("table combination created", slog.Int("combinationID", combination.ID))
+
+		render.JSON(w, r, resp.OKWithData(combination))
+	}
+}
+
+// List возвращает действующие комбинации столов, чтобы клиент мог выбрать нужную при бронировании.
+func List(log *slog.Logger, tableService *tablesrv.TableService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.combinations.List"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		combinations, err := tableService.GetCombinations(r.Context())
+		if err != nil {
+			log.Error("failed to get combinations", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to fetch table combinations"))
+
+			return
+		}
+
+		render.JSON(w, r, resp.OKWithData(combinations))
+	}
+}
+
+// Delete убирает комбинацию столов из доступных для брони. Доступно только админам.
+func Delete(log *slog.Logger, authClient *grpc.Client, tableService *tablesrv.TableService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.combinations.Delete"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		if _, ok := auth.RequireAdmin(log, authClient, w, r); !ok {
+			return
+		}
+
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || id <= 0 {
+			log.Warn("invalid combination id", slog.String("id", chi.URLParam(r, "id")))
+
+			render.JSON(w, r, resp.Error("Invalid combination id"))
+
+			return
+		}
+
+		if err := tableService.DeleteCombination(r.Context(), id); err != nil {
+			if errors.Is(err, storage.ErrCombinationNotFound) {
+				render.JSON(w, r, resp.Error("Table combination not found"))
+
+				return
+			}
+
+			log.Error("failed to delete combination", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to delete table combination"))
+
+			return
+		}
+
+		log.Info("table combination deleted", slog.Int("combinationID", id))
+
+		render.JSON(w, r, resp.OK())
+	}
+}
@@ -0,0 +1,135 @@
+package customers
+
+import (
+	"errors"
+	"log/slog"
+	"main_service/internal/clients/sso/grpc"
+	customersrv "main_service/internal/http-server/handlers/middleware/customers"
+	"main_service/internal/lib/api/auth"
+	resp "main_service/internal/lib/api/response"
+	"main_service/internal/lib/logger/sl"
+	"main_service/internal/storage"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator"
+)
+
+// UpdateRequest - теги и заметки о госте. Прежние значения заменяются целиком.
+type UpdateRequest struct {
+	Tags  []string `json:"tags" validate:"max=20,dive,required,max=32"`
+	Notes string   `json:"notes" validate:"max=2000"`
+}
+
+// Get возвращает профиль гостя: сколько раз приходил и не пришёл, последний визит, любимый стол,
+// аллергены, теги и заметки. Доступно только админам.
+func Get(log *slog.Logger, authClient *grpc.Client, customerService *customersrv.CustomerService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.customers.Get"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		_, userID, ok := parseRequest(log, authClient, w, r)
+		if !ok {
+			return
+		}
+
+		profile, err := customerService.GetProfile(r.Context(), userID)
+		if err != nil {
+			if errors.Is(err, storage.ErrUserNotFound) {
+				render.JSON(w, r, resp.Error("User not found"))
+
+				return
+			}
+
+			log.Error("failed to get customer profile", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to fetch customer profile"))
+
+			return
+		}
+
+		render.JSON(w, r, resp.OKWithData(profile))
+	}
+}
+
+// Update заменяет теги и заметки о госте. Доступно только админам.
+func Update(log *slog.Logger, authClient *grpc.Client, customerService *customersrv.CustomerService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.customers.Update"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		adminID, userID, ok := parseRequest(log, authClient, w, r)
+		if !ok {
+			return
+		}
+
+		var req UpdateRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to decode request"))
+
+			return
+		}
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			log.Error("invalid request", sl.Err(err))
+
+			render.JSON(w, r, resp.ValidationError(validateErr))
+
+			return
+		}
+
+		profile, err := customerService.UpdateProfile(r.Context(), userID, req.Tags, req.Notes, adminID)
+		if err != nil {
+			if errors.Is(err, storage.ErrUserNotFound) {
+				render.JSON(w, r, resp.Error("User not found"))
+
+				return
+			}
+
+			log.Error("failed to update customer profile", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to update customer profile"))
+
+			return
+		}
+
+		log.Info("customer profile updated", slog.Int64("userID", userID), slog.Int64("adminID", adminID))
+
+		render.JSON(w, r, resp.OKWithData(profile))
+	}
+}
+
+// parseRequest проверяет, что запрос сделал админ, и возвращает его id и id гостя из пути.
+// При ошибке ответ уже записан.
+func parseRequest(log *slog.Logger, authClient *grpc.Client, w http.ResponseWriter, r *http.Request) (int64, int64, bool) {
+	adminID, ok := auth.RequireAdmin(log, authClient, w, r)
+	if !ok {
+		return 0, 0, false
+	}
+
+	userID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil || userID <= 0 {
+		log.Warn("invalid user id", slog.String("id", chi.URLParam(r, "id")))
+
+		render.JSON(w, r, resp.Error("Invalid user id"))
+
+		return 0, 0, false
+	}
+
+	return adminID, userID, true
+}
@@ -0,0 +1,158 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"main_service/internal/clients/sso/grpc"
+	eventsrv "main_service/internal/http-server/handlers/middleware/events"
+	"main_service/internal/lib/api/auth"
+	resp "main_service/internal/lib/api/response"
+	"main_service/internal/lib/logger/sl"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+)
+
+// heartbeatInterval - как часто отправляется комментарий, чтобы прокси не закрывали простаивающее соединение.
+const heartbeatInterval = 15 * time.Second
+
+// Bookings отдаёт события броней (created, cancelled, changed) потоком Server-Sent Events. Доступно только админам.
+// Клиент, переподключаясь, передаёт id последнего полученного события в заголовке Last-Event-ID
+// или в параметре lastEventId и сначала получает всё, что пропустил.
+func Bookings(log *slog.Logger, authClient *grpc.Client, broker *eventsrv.Broker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.events.Bookings"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		adminID, ok := auth.RequireAdmin(log, authClient, w, r)
+		if !ok {
+			return
+		}
+
+		lastEventID := r.Header.Get("Last-Event-ID")
+		if lastEventID == "" {
+			lastEventID = r.URL.Query().Get("lastEventId")
+		}
+
+		var (
+			lastID int64
+			cursor models.EventCursor
+		)
+		if lastEventID != "" {
+			var err error
+
+			lastID, err = strconv.ParseInt(lastEventID, 10, 64)
+			if err != nil || lastID < 0 {
+				render.JSON(w, r, resp.Error("Invalid Last-Event-ID"))
+
+				return
+			}
+
+			cursor, err = broker.Cursor(r.Context(), lastID)
+			if errors.Is(err, storage.ErrUnknownEventID) {
+				render.JSON(w, r, resp.Error("Invalid Last-Event-ID"))
+
+				return
+			}
+			if err != nil {
+				log.Error("failed to get booking event cursor", sl.Err(err))
+
+				render.JSON(w, r, resp.Error("Failed to get booking events"))
+
+				return
+			}
+		}
+
+		// Поток живёт дольше, чем WriteTimeout сервера.
+		rc := http.NewResponseController(w)
+		if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+			log.Error("failed to disable write deadline", sl.Err(err))
+		}
+
+		// Подписываемся до повтора пропущенных событий, чтобы ничего не потерять между ними.
+		// Дубликаты отсекаются по позиции в ленте.
+		events, unsubscribe := broker.Subscribe()
+		defer unsubscribe()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+
+		send := func(e models.BookingEvent) error {
+			if !cursor.Before(e) {
+				return nil
+			}
+
+			if err := writeEvent(w, e); err != nil {
+				return err
+			}
+			cursor = e.Cursor()
+
+			return rc.Flush()
+		}
+
+		if lastEventID != "" {
+			if err := broker.Replay(r.Context(), cursor, send); err != nil {
+				log.Error("failed to replay booking events", sl.Err(err))
+
+				return
+			}
+		} else if err := rc.Flush(); err != nil {
+			return
+		}
+
+		log.Info("admin subscribed to booking events", slog.Int64("userID", adminID), slog.Int64("lastEventID", lastID))
+
+		heartbeat := time.NewTicker(heartbeatInterval)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case e, ok := <-events:
+				if !ok {
+					log.Warn("subscriber is too slow, closing stream", slog.Int64("userID", adminID))
+
+					return
+				}
+
+				if err := send(e); err != nil {
+					return
+				}
+			case <-heartbeat.C:
+				if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+					return
+				}
+
+				if err := rc.Flush(); err != nil {
+					return
+				}
+			}
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, e models.BookingEvent) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.EventType, data)
+
+	return err
+}
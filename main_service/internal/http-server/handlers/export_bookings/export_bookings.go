@@ -0,0 +1,166 @@
+package exportbookings
+
+import (
+	"encoding/csv"
+	"fmt"
+	"log/slog"
+	"main_service/internal/clients/sso/grpc"
+	getbookings "main_service/internal/http-server/handlers/get_bookings"
+	bookingsrv "main_service/internal/http-server/handlers/middleware/booking"
+	"main_service/internal/lib/api/auth"
+	resp "main_service/internal/lib/api/response"
+	"main_service/internal/lib/logger/sl"
+	"main_service/internal/models"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator"
+)
+
+// utf8BOM нужен, чтобы Excel открывал файл в UTF-8 и корректно показывал кириллицу.
+const utf8BOM = "\uFEFF"
+
+// flushEvery - через сколько строк сбрасывать буфер клиенту.
+const flushEvery = 100
+
+var header = []string{
+	"id", "booking_time", "table_id", "tables", "party_size", "status",
+	"occasion", "allergens", "notes", "email", "first_name", "last_name",
+}
+
+// New выгружает брони в CSV с теми же фильтрами, что и GET /bookings. Доступно только админам.
+func New(log *slog.Logger, authClient *grpc.Client, bookingService *bookingsrv.BookingService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.export-bookings.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		req, err := getbookings.ParseRequest(r)
+		if err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			log.Error("invalid request", sl.Err(err))
+
+			render.JSON(w, r, resp.ValidationError(validateErr))
+
+			return
+		}
+
+		filter, err := req.Filter()
+		if err != nil {
+			log.Warn("invalid date filter", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Dates must be in YYYY-MM-DD format"))
+
+			return
+		}
+
+		if _, ok := auth.RequireAdmin(log, authClient, w, r); !ok {
+			return
+		}
+
+		// Выгрузка за большой период пишется дольше, чем WriteTimeout сервера, и обрывалась бы на середине файла.
+		rc := http.NewResponseController(w)
+		if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+			log.Error("failed to disable write deadline", sl.Err(err))
+		}
+
+		filename := fmt.Sprintf("bookings_%s.csv", time.Now().Format("2006-01-02"))
+
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+		if _, err := w.Write([]byte(utf8BOM)); err != nil {
+			log.Error("failed to write csv", sl.Err(err))
+
+			return
+		}
+
+		cw := csv.NewWriter(w)
+		flusher, _ := w.(http.Flusher)
+
+		if err := cw.Write(header); err != nil {
+			log.Error("failed to write csv", sl.Err(err))
+
+			return
+		}
+
+		count := 0
+		err = bookingService.ExportBookings(r.Context(), filter, func(b models.BookingInfo) error {
+			err := cw.Write([]string{
+				strconv.FormatInt(b.ID, 10),
+				b.BookingTime.Format("2006-01-02 15:04"),
+				strconv.Itoa(int(b.TableID)),
+				joinTables(b.TableIDs),
+				strconv.Itoa(int(b.PartySize)),
+				b.Status,
+				escapeCell(b.Occasion),
+				escapeCell(strings.Join(b.Allergens, ", ")),
+				escapeCell(b.Notes),
+				escapeCell(b.Email),
+				escapeCell(b.FirstName),
+				escapeCell(b.LastName),
+			})
+			if err != nil {
+				return err
+			}
+
+			count++
+			if count%flushEvery == 0 {
+				cw.Flush()
+				if flusher != nil {
+					flusher.Flush()
+				}
+			}
+
+			return cw.Error()
+		})
+
+		cw.Flush()
+
+		// Заголовки уже отправлены, поэтому об ошибке можно только написать в лог.
+		if err != nil {
+			log.Error("failed to export bookings", sl.Err(err))
+
+			return
+		}
+
+		if err := cw.Error(); err != nil {
+			log.Error("failed to write csv", sl.Err(err))
+
+			return
+		}
+
+		log.Info("bookings exported successfully",
+			slog.Int("count", count),
+			slog.String("mode", req.Mode),
+		)
+	}
+}
+
+// joinTables перечисляет столы брони через "+", например 3+4+5.
+func joinTables(tableIDs []int16) string {
+	tables := make([]string, 0, len(tableIDs))
+	for _, id := range tableIDs {
+		tables = append(tables, strconv.Itoa(int(id)))
+	}
+
+	return strings.Join(tables, "+")
+}
+
+// escapeCell защищает от CSV-инъекции: значение, введённое гостем, которое Excel принял бы за формулу,
+// начинается с апострофа и открывается как текст.
+func escapeCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+
+	return value
+}
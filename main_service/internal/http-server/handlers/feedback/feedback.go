@@ -0,0 +1,125 @@
+package feedback
+
+import (
+	"errors"
+	"log/slog"
+	feedbacksrv "main_service/internal/http-server/handlers/middleware/feedback"
+	resp "main_service/internal/lib/api/response"
+	"main_service/internal/lib/feedback"
+	"main_service/internal/lib/logger/sl"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator"
+)
+
+// Request - отзыв гостя о визите.
+type Request struct {
+	Rating  int    `json:"rating" validate:"required,min=1,max=5"`
+	Comment string `json:"comment" validate:"max=2000"`
+}
+
+// Submit принимает отзыв со страницы, на которую ведёт ссылка из письма: POST /feedback/{id}?expires=&sig=
+// Авторизация не нужна, гостя подтверждает подпись ссылки.
+func Submit(log *slog.Logger, feedbackService *feedbacksrv.FeedbackService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.feedback.Submit"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		bookingID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil || bookingID <= 0 {
+			log.Warn("invalid booking id", slog.String("id", chi.URLParam(r, "id")))
+
+			render.JSON(w, r, resp.Error("Invalid booking id"))
+
+			return
+		}
+
+		query := r.URL.Query()
+
+		expiresUnix, err := strconv.ParseInt(query.Get("expires"), 10, 64)
+		if err != nil || query.Get("sig") == "" {
+			log.Warn("feedback link is incomplete", slog.Int64("bookingID", bookingID))
+
+			render.JSON(w, r, resp.Error("Invalid feedback link"))
+
+			return
+		}
+
+		var req Request
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to decode request"))
+
+			return
+		}
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			log.Error("invalid request", sl.Err(err))
+
+			render.JSON(w, r, resp.ValidationError(validateErr))
+
+			return
+		}
+
+		review, err := feedbackService.SubmitReview(
+			r.Context(),
+			models.Review{
+				BookingID: bookingID,
+				Rating:    req.Rating,
+				Comment:   strings.TrimSpace(req.Comment),
+			},
+			time.Unix(expiresUnix, 0),
+			query.Get("sig"),
+		)
+		if err != nil {
+			if errors.Is(err, feedback.ErrInvalidSignature) {
+				log.Warn("invalid feedback link signature", slog.Int64("bookingID", bookingID))
+
+				render.JSON(w, r, resp.Error("Invalid feedback link"))
+
+				return
+			} else if errors.Is(err, feedback.ErrLinkExpired) {
+				render.JSON(w, r, resp.Error("Feedback link has expired"))
+
+				return
+			} else if errors.Is(err, storage.ErrBookingNotFound) {
+				render.JSON(w, r, resp.Error("Booking not found"))
+
+				return
+			} else if errors.Is(err, storage.ErrUnexpectedStatus) {
+				render.JSON(w, r, resp.Error("Only completed visits can be reviewed"))
+
+				return
+			} else if errors.Is(err, storage.ErrReviewExists) {
+				render.JSON(w, r, resp.Error("This visit has already been reviewed"))
+
+				return
+			}
+
+			log.Error("failed to save review", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to save review"))
+
+			return
+		}
+
+		log.Info("review saved", slog.Int64("bookingID", bookingID), slog.Int("rating", review.Rating))
+
+		render.JSON(w, r, resp.OKWithData(review))
+	}
+}
@@ -0,0 +1,130 @@
+package floorplan
+
+import (
+	"log/slog"
+	"main_service/internal/clients/sso/grpc"
+	tablesrv "main_service/internal/http-server/handlers/middleware/tables"
+	"main_service/internal/lib/api/auth"
+	resp "main_service/internal/lib/api/response"
+	"main_service/internal/lib/logger/sl"
+	"main_service/internal/models"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator"
+)
+
+// TableRequest - зал и место стола на плане зала.
+type TableRequest struct {
+	Zone     string  `json:"zone" validate:"required,max=50"`
+	X        float32 `json:"x" validate:"gte=0"`
+	Y        float32 `json:"y" validate:"gte=0"`
+	Width    float32 `json:"width" validate:"gt=0"`
+	Height   float32 `json:"height" validate:"gt=0"`
+	Rotation int16   `json:"rotation" validate:"gte=0,lt=360"`
+	Shape    string  `json:"shape" validate:"required,oneof=square round rect"`
+	Seats    int16   `json:"seats" validate:"gt=0,lte=50"`
+	// Attributes - особенности места, по которым гости выбирают стол.
+	Attributes []string `json:"attributes" validate:"max=5,unique,dive,oneof=window terrace booth quiet wheelchair_accessible"`
+}
+
+// Get возвращает план зала с текущим состоянием каждого стола для планшета хостес. Доступно только админам.
+func Get(log *slog.Logger, authClient *grpc.Client, tableService *tablesrv.TableService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.floorplan.Get"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		if _, ok := auth.RequireAdmin(log, authClient, w, r); !ok {
+			return
+		}
+
+		plan, err := tableService.FloorPlan(r.Context(), time.Now())
+		if err != nil {
+			log.Error("failed to get floor plan", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to fetch floor plan"))
+
+			return
+		}
+
+		render.JSON(w, r, resp.OKWithData(plan))
+	}
+}
+
+// SaveTable добавляет стол на план зала или меняет его зал и расположение. Доступно только админам.
+func SaveTable(log *slog.Logger, authClient *grpc.Client, tableService *tablesrv.TableService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.floorplan.SaveTable"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		if _, ok := auth.RequireAdmin(log, authClient, w, r); !ok {
+			return
+		}
+
+		tableID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 16)
+		if err != nil || tableID <= 0 {
+			log.Warn("invalid table id", slog.String("id", chi.URLParam(r, "id")))
+
+			render.JSON(w, r, resp.Error("Invalid table id"))
+
+			return
+		}
+
+		var req TableRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to decode request"))
+
+			return
+		}
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			log.Error("invalid request", sl.Err(err))
+
+			render.JSON(w, r, resp.ValidationError(validateErr))
+
+			return
+		}
+
+		table := models.Table{
+			ID:       int16(tableID),
+			Zone:     req.Zone,
+			X:        req.X,
+			Y:        req.Y,
+			Width:    req.Width,
+			Height:   req.Height,
+			Rotation: req.Rotation,
+			Shape:    req.Shape,
+			Seats:    req.Seats,
+
+			Attributes: req.Attributes,
+		}
+
+		if err := tableService.SaveTable(r.Context(), table); err != nil {
+			log.Error("failed to save table", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to save table"))
+
+			return
+		}
+
+		log.Info("table saved", slog.Int("tableID", int(tableID)))
+
+		render.JSON(w, r, resp.OKWithData(table))
+	}
+}
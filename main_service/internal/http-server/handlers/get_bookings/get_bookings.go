@@ -1,6 +1,7 @@
 package getbookings
 
 import (
+	"errors"
 	"log/slog"
 	"main_service/internal/clients/sso/grpc"
 	bookingsrv "main_service/internal/http-server/handlers/middleware/booking"
@@ -8,14 +9,59 @@ import (
 	"main_service/internal/lib/logger/sl"
 	"main_service/internal/models"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/middleware"
 	"github.com/go-chi/render"
 	"github.com/go-playground/validator"
 )
 
+const dateLayout = "2006-01-02"
+
 type GetBookingsRequest struct {
-	Mode string `query:"mode" validate:"required,oneof=all active"`
+	Mode string `query:"mode" validate:"required,oneof=all active pending"`
+	From string `query:"from" validate:"omitempty,len=10"`
+	To   string `query:"to" validate:"omitempty,len=10"`
+}
+
+// ErrInvalidDate возвращается, если from или to не в формате YYYY-MM-DD.
+var ErrInvalidDate = errors.New("dates must be in YYYY-MM-DD format")
+
+// Filter переводит параметры запроса в фильтр броней. Дата to включается в период.
+func (req GetBookingsRequest) Filter() (models.BookingFilter, error) {
+	filter := models.BookingFilter{Mode: req.Mode}
+
+	if req.From != "" {
+		from, err := time.Parse(dateLayout, req.From)
+		if err != nil {
+			return models.BookingFilter{}, ErrInvalidDate
+		}
+		filter.From = &from
+	}
+
+	if req.To != "" {
+		to, err := time.Parse(dateLayout, req.To)
+		if err != nil {
+			return models.BookingFilter{}, ErrInvalidDate
+		}
+		to = to.AddDate(0, 0, 1)
+		filter.To = &to
+	}
+
+	return filter, nil
+}
+
+// ParseRequest читает и валидирует параметры списка броней из query-строки.
+func ParseRequest(r *http.Request) (GetBookingsRequest, error) {
+	query := r.URL.Query()
+
+	req := GetBookingsRequest{
+		Mode: query.Get("mode"),
+		From: query.Get("from"),
+		To:   query.Get("to"),
+	}
+
+	return req, validator.New().Struct(req)
 }
 
 func New(log *slog.Logger, authClient *grpc.Client, bookingService *bookingsrv.BookingService) http.HandlerFunc {
@@ -27,11 +73,8 @@ func New(log *slog.Logger, authClient *grpc.Client, bookingService *bookingsrv.B
 			slog.String("request_id", middleware.GetReqID(r.Context())),
 		)
 
-		req := GetBookingsRequest{
-			Mode: r.URL.Query().Get("mode"),
-		}
-
-		if err := validator.New().Struct(req); err != nil {
+		req, err := ParseRequest(r)
+		if err != nil {
 			validateErr := err.(validator.ValidationErrors)
 
 			log.Error("invalid request", sl.Err(err))
@@ -41,6 +84,15 @@ func New(log *slog.Logger, authClient *grpc.Client, bookingService *bookingsrv.B
 			return
 		}
 
+		filter, err := req.Filter()
+		if err != nil {
+			log.Warn("invalid date filter", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Dates must be in YYYY-MM-DD format"))
+
+			return
+		}
+
 		userID, ok := r.Context().Value(models.ContextKey("uid")).(float64)
 		if !ok || userID <= 0 {
 			log.Error("unauthorized: no userID in context")
@@ -68,7 +120,7 @@ func New(log *slog.Logger, authClient *grpc.Client, bookingService *bookingsrv.B
 		}
 
 		// достаём список броней
-		bookings, err := bookingService.GetBookings(r.Context(), req.Mode)
+		bookings, err := bookingService.GetBookings(r.Context(), filter)
 		if err != nil {
 			log.Error("failed to get bookings", sl.Err(err))
 			render.JSON(w, r, resp.Error("Failed to fetch bookings"))
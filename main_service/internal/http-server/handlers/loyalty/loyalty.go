@@ -0,0 +1,167 @@
+package loyalty
+
+import (
+	"errors"
+	"log/slog"
+	"main_service/internal/clients/sso/grpc"
+	loyaltysrv "main_service/internal/http-server/handlers/middleware/loyalty"
+	"main_service/internal/lib/api/auth"
+	resp "main_service/internal/lib/api/response"
+	"main_service/internal/lib/logger/sl"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator"
+)
+
+// AdjustRequest - ручная корректировка баланса: положительные Points начисляются, отрицательные списываются.
+type AdjustRequest struct {
+	Points int    `json:"points" validate:"required,min=-100000,max=100000"`
+	Reason string `json:"reason" validate:"required,max=500"`
+}
+
+// Me возвращает баланс баллов лояльности текущего пользователя и историю операций.
+func Me(log *slog.Logger, loyaltyService *loyaltysrv.LoyaltyService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.loyalty.Me"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userID, ok := r.Context().Value(models.ContextKey("uid")).(float64)
+		if !ok || userID <= 0 {
+			log.Error("unauthorized: no userID in context")
+
+			render.JSON(w, r, resp.Error("Unauthorized"))
+
+			return
+		}
+
+		account, err := loyaltyService.GetAccount(r.Context(), int64(userID))
+		if err != nil {
+			log.Error("failed to get loyalty account", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to fetch loyalty balance"))
+
+			return
+		}
+
+		render.JSON(w, r, resp.OKWithData(account))
+	}
+}
+
+// Get возвращает баланс баллов пользователя и историю операций. Доступно только админам.
+func Get(log *slog.Logger, authClient *grpc.Client, loyaltyService *loyaltysrv.LoyaltyService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.loyalty.Get"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		if _, ok := auth.RequireAdmin(log, authClient, w, r); !ok {
+			return
+		}
+
+		userID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil || userID <= 0 {
+			log.Warn("invalid user id", slog.String("id", chi.URLParam(r, "id")))
+
+			render.JSON(w, r, resp.Error("Invalid user id"))
+
+			return
+		}
+
+		account, err := loyaltyService.GetAccount(r.Context(), userID)
+		if err != nil {
+			log.Error("failed to get loyalty account", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to fetch loyalty balance"))
+
+			return
+		}
+
+		render.JSON(w, r, resp.OKWithData(account))
+	}
+}
+
+// Adjust вручную начисляет или списывает баллы пользователя с указанием причины. Доступно только админам.
+func Adjust(log *slog.Logger, authClient *grpc.Client, loyaltyService *loyaltysrv.LoyaltyService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.loyalty.Adjust"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		adminID, ok := auth.RequireAdmin(log, authClient, w, r)
+		if !ok {
+			return
+		}
+
+		userID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil || userID <= 0 {
+			log.Warn("invalid user id", slog.String("id", chi.URLParam(r, "id")))
+
+			render.JSON(w, r, resp.Error("Invalid user id"))
+
+			return
+		}
+
+		var req AdjustRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to decode request"))
+
+			return
+		}
+
+		req.Reason = strings.TrimSpace(req.Reason)
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			log.Error("invalid request", sl.Err(err))
+
+			render.JSON(w, r, resp.ValidationError(validateErr))
+
+			return
+		}
+
+		entry, err := loyaltyService.Adjust(r.Context(), userID, req.Points, req.Reason, adminID)
+		if err != nil {
+			if errors.Is(err, storage.ErrUserNotFound) {
+				log.Warn("failed to adjust loyalty balance, user not found", slog.Int64("userID", userID))
+
+				render.JSON(w, r, resp.Error("User not found"))
+
+				return
+			}
+
+			log.Error("failed to adjust loyalty balance", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to adjust loyalty balance"))
+
+			return
+		}
+
+		log.Info("loyalty balance adjusted",
+			slog.Int64("userID", userID),
+			slog.Int("points", req.Points),
+			slog.Int64("adminID", adminID),
+		)
+
+		render.JSON(w, r, resp.OKWithData(entry))
+	}
+}
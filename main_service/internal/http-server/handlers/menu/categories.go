@@ -0,0 +1,190 @@
+package menu
+
+import (
+	"errors"
+	"log/slog"
+	"main_service/internal/clients/sso/grpc"
+	menusrv "main_service/internal/http-server/handlers/middleware/menu"
+	"main_service/internal/lib/api/auth"
+	resp "main_service/internal/lib/api/response"
+	"main_service/internal/lib/logger/sl"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator"
+)
+
+// CategoryRequest - раздел меню. Position задаёт порядок разделов, меньше - выше.
+type CategoryRequest struct {
+	Name        string `json:"name" validate:"required,max=100"`
+	Description string `json:"description" validate:"max=500"`
+	Position    int    `json:"position" validate:"gte=0"`
+}
+
+func (req CategoryRequest) category(id int64) models.MenuCategory {
+	return models.MenuCategory{
+		ID:          id,
+		Name:        strings.TrimSpace(req.Name),
+		Description: strings.TrimSpace(req.Description),
+		Position:    req.Position,
+	}
+}
+
+// CreateCategory добавляет раздел меню. Доступно только админам.
+func CreateCategory(log *slog.Logger, authClient *grpc.Client, menuService *menusrv.MenuService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.menu.CreateCategory"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		if _, ok := auth.RequireAdmin(log, authClient, w, r); !ok {
+			return
+		}
+
+		req, ok := decodeCategory(log, w, r)
+		if !ok {
+			return
+		}
+
+		category, err := menuService.CreateCategory(r.Context(), req.category(0))
+		if err != nil {
+			log.Error("failed to create menu category", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to create menu category"))
+
+			return
+		}
+
+		log.Info("menu category created", slog.Int64("categoryID", category.ID))
+
+		render.JSON(w, r, resp.OKWithData(category))
+	}
+}
+
+// UpdateCategory меняет раздел меню. Доступно только админам.
+func UpdateCategory(log *slog.Logger, authClient *grpc.Client, menuService *menusrv.MenuService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.menu.UpdateCategory"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		if _, ok := auth.RequireAdmin(log, authClient, w, r); !ok {
+			return
+		}
+
+		id := parseID(r)
+		if id == 0 {
+			log.Warn("invalid menu category id", slog.String("id", chi.URLParam(r, "id")))
+
+			render.JSON(w, r, resp.Error("Invalid menu category id"))
+
+			return
+		}
+
+		req, ok := decodeCategory(log, w, r)
+		if !ok {
+			return
+		}
+
+		category := req.category(id)
+		if err := menuService.UpdateCategory(r.Context(), category); err != nil {
+			if errors.Is(err, storage.ErrCategoryNotFound) {
+				render.JSON(w, r, resp.Error("Menu category not found"))
+
+				return
+			}
+
+			log.Error("failed to update menu category", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to update menu category"))
+
+			return
+		}
+
+		log.Info("menu category updated", slog.Int64("categoryID", id))
+
+		render.JSON(w, r, resp.OKWithData(category))
+	}
+}
+
+// DeleteCategory убирает пустой раздел из меню. Доступно только админам.
+func DeleteCategory(log *slog.Logger, authClient *grpc.Client, menuService *menusrv.MenuService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.menu.DeleteCategory"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		if _, ok := auth.RequireAdmin(log, authClient, w, r); !ok {
+			return
+		}
+
+		id := parseID(r)
+		if id == 0 {
+			log.Warn("invalid menu category id", slog.String("id", chi.URLParam(r, "id")))
+
+			render.JSON(w, r, resp.Error("Invalid menu category id"))
+
+			return
+		}
+
+		if err := menuService.DeleteCategory(r.Context(), id); err != nil {
+			if errors.Is(err, storage.ErrCategoryNotFound) {
+				render.JSON(w, r, resp.Error("Menu category not found"))
+
+				return
+			} else if errors.Is(err, storage.ErrCategoryNotEmpty) {
+				render.JSON(w, r, resp.Error("Menu category still has dishes"))
+
+				return
+			}
+
+			log.Error("failed to delete menu category", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to delete menu category"))
+
+			return
+		}
+
+		log.Info("menu category deleted", slog.Int64("categoryID", id))
+
+		render.JSON(w, r, resp.OK())
+	}
+}
+
+// decodeCategory читает и проверяет раздел меню из тела запроса. При ошибке ответ уже записан.
+func decodeCategory(log *slog.Logger, w http.ResponseWriter, r *http.Request) (CategoryRequest, bool) {
+	var req CategoryRequest
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		log.Error("failed to decode request body", sl.Err(err))
+
+		render.JSON(w, r, resp.Error("Failed to decode request"))
+
+		return CategoryRequest{}, false
+	}
+
+	if err := validator.New().Struct(req); err != nil {
+		validateErr := err.(validator.ValidationErrors)
+
+		log.Error("invalid request", sl.Err(err))
+
+		render.JSON(w, r, resp.ValidationError(validateErr))
+
+		return CategoryRequest{}, false
+	}
+
+	return req, true
+}
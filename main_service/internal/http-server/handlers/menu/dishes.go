@@ -0,0 +1,213 @@
+package menu
+
+import (
+	"errors"
+	"log/slog"
+	"main_service/internal/clients/sso/grpc"
+	menusrv "main_service/internal/http-server/handlers/middleware/menu"
+	"main_service/internal/lib/api/auth"
+	resp "main_service/internal/lib/api/response"
+	"main_service/internal/lib/logger/sl"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator"
+)
+
+// DishRequest - блюдо меню. Price - в копейках. IsAvailable не указан - блюдо доступно.
+type DishRequest struct {
+	CategoryID  int64    `json:"categoryId" validate:"required,gt=0"`
+	Name        string   `json:"name" validate:"required,max=100"`
+	Description string   `json:"description" validate:"max=1000"`
+	Price       int64    `json:"price" validate:"gte=0"`
+	Allergens   []string `json:"allergens" validate:"max=14,unique,dive,oneof=gluten crustaceans eggs fish peanuts soy milk nuts celery mustard sesame sulphites lupin molluscs"`
+	IsAvailable *bool    `json:"isAvailable"`
+	Position    int      `json:"position" validate:"gte=0"`
+}
+
+func (req DishRequest) dish(id int64) models.Dish {
+	isAvailable := true
+	if req.IsAvailable != nil {
+		isAvailable = *req.IsAvailable
+	}
+
+	return models.Dish{
+		ID:          id,
+		CategoryID:  req.CategoryID,
+		Name:        strings.TrimSpace(req.Name),
+		Description: strings.TrimSpace(req.Description),
+		Price:       req.Price,
+		Allergens:   req.Allergens,
+		IsAvailable: isAvailable,
+		Position:    req.Position,
+	}
+}
+
+// CreateDish добавляет блюдо в раздел меню. Доступно только админам.
+func CreateDish(log *slog.Logger, authClient *grpc.Client, menuService *menusrv.MenuService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.menu.CreateDish"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		if _, ok := auth.RequireAdmin(log, authClient, w, r); !ok {
+			return
+		}
+
+		req, ok := decodeDish(log, w, r)
+		if !ok {
+			return
+		}
+
+		dish, err := menuService.CreateDish(r.Context(), req.dish(0))
+		if err != nil {
+			if errors.Is(err, storage.ErrCategoryNotFound) {
+				render.JSON(w, r, resp.Error("Menu category not found"))
+
+				return
+			}
+
+			log.Error("failed to create dish", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to create dish"))
+
+			return
+		}
+
+		log.Info("dish created", slog.Int64("dishID", dish.ID), slog.Int64("categoryID", dish.CategoryID))
+
+		render.JSON(w, r, resp.OKWithData(dish))
+	}
+}
+
+// UpdateDish меняет блюдо: раздел, цену, аллергены и доступность. Доступно только админам.
+func UpdateDish(log *slog.Logger, authClient *grpc.Client, menuService *menusrv.MenuService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.menu.UpdateDish"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		if _, ok := auth.RequireAdmin(log, authClient, w, r); !ok {
+			return
+		}
+
+		id := parseID(r)
+		if id == 0 {
+			log.Warn("invalid dish id", slog.String("id", chi.URLParam(r, "id")))
+
+			render.JSON(w, r, resp.Error("Invalid dish id"))
+
+			return
+		}
+
+		req, ok := decodeDish(log, w, r)
+		if !ok {
+			return
+		}
+
+		dish := req.dish(id)
+		if err := menuService.UpdateDish(r.Context(), dish); err != nil {
+			if errors.Is(err, storage.ErrDishNotFound) {
+				render.JSON(w, r, resp.Error("Dish not found"))
+
+				return
+			} else if errors.Is(err, storage.ErrCategoryNotFound) {
+				render.JSON(w, r, resp.Error("Menu category not found"))
+
+				return
+			}
+
+			log.Error("failed to update dish", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to update dish"))
+
+			return
+		}
+
+		log.Info("dish updated", slog.Int64("dishID", id))
+
+		if dish.Allergens == nil {
+			dish.Allergens = []string{}
+		}
+
+		render.JSON(w, r, resp.OKWithData(dish))
+	}
+}
+
+// DeleteDish убирает блюдо из меню. Доступно только админам.
+func DeleteDish(log *slog.Logger, authClient *grpc.Client, menuService *menusrv.MenuService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.menu.DeleteDish"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		if _, ok := auth.RequireAdmin(log, authClient, w, r); !ok {
+			return
+		}
+
+		id := parseID(r)
+		if id == 0 {
+			log.Warn("invalid dish id", slog.String("id", chi.URLParam(r, "id")))
+
+			render.JSON(w, r, resp.Error("Invalid dish id"))
+
+			return
+		}
+
+		if err := menuService.DeleteDish(r.Context(), id); err != nil {
+			if errors.Is(err, storage.ErrDishNotFound) {
+				render.JSON(w, r, resp.Error("Dish not found"))
+
+				return
+			}
+
+			log.Error("failed to delete dish", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to delete dish"))
+
+			return
+		}
+
+		log.Info("dish deleted", slog.Int64("dishID", id))
+
+		render.JSON(w, r, resp.OK())
+	}
+}
+
+// decodeDish читает и проверяет блюдо из тела запроса. При ошибке ответ уже записан.
+func decodeDish(log *slog.Logger, w http.ResponseWriter, r *http.Request) (DishRequest, bool) {
+	var req DishRequest
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		log.Error("failed to decode request body", sl.Err(err))
+
+		render.JSON(w, r, resp.Error("Failed to decode request"))
+
+		return DishRequest{}, false
+	}
+
+	if err := validator.New().Struct(req); err != nil {
+		validateErr := err.(validator.ValidationErrors)
+
+		log.Error("invalid request", sl.Err(err))
+
+		render.JSON(w, r, resp.ValidationError(validateErr))
+
+		return DishRequest{}, false
+	}
+
+	return req, true
+}
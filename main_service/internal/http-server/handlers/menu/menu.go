@@ -0,0 +1,47 @@
+package menu
+
+import (
+	"log/slog"
+	menusrv "main_service/internal/http-server/handlers/middleware/menu"
+	resp "main_service/internal/lib/api/response"
+	"main_service/internal/lib/logger/sl"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+)
+
+// Get возвращает меню для сайта: разделы с блюдами, ценами, аллергенами и доступностью. Токен не нужен.
+func Get(log *slog.Logger, menuService *menusrv.MenuService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.menu.Get"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		menu, err := menuService.GetMenu(r.Context())
+		if err != nil {
+			log.Error("failed to get menu", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to fetch menu"))
+
+			return
+		}
+
+		render.JSON(w, r, resp.OKWithData(menu))
+	}
+}
+
+// parseID возвращает id из пути запроса или 0, если он некорректен.
+func parseID(r *http.Request) int64 {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil || id <= 0 {
+		return 0
+	}
+
+	return id
+}
@@ -0,0 +1,173 @@
+package bookingsrv
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"main_service/internal/models"
+)
+
+const (
+	// maxAlternativeTimes - сколько вариантов времени для тех же столов предлагается гостю.
+	maxAlternativeTimes = 3
+	// maxAlternativeTables - сколько других столов предлагается гостю.
+	maxAlternativeTables = 5
+	// alternativeDaysRange - на сколько дней вперёд и назад ищется свободное время для тех же столов.
+	alternativeDaysRange = 7
+)
+
+// SuggestAlternatives подбирает замену для брони, которую не удалось сделать из-за занятого стола:
+// ближайшее время в тот же или соседние дни, когда те же столы свободны, и другие столы, свободные в запрошенное время.
+// Время раньше notBefore не предлагается.
+// Если стол подбирался автоматически, предлагаются только другие столы.
+func (s *BookingService) SuggestAlternatives(ctx context.Context, booking models.Booking, notBefore time.Time) (models.BookingAlternatives, error) {
+	times := []time.Time{}
+
+	if booking.TableID != 0 || booking.CombinationID != 0 {
+		var err error
+		if booking, err = s.resolveTables(ctx, booking); err != nil {
+			return models.BookingAlternatives{}, err
+		}
+
+		if times, err = s.alternativeTimes(ctx, booking, notBefore); err != nil {
+			return models.BookingAlternatives{}, err
+		}
+	}
+
+	tables, err := s.alternativeTables(ctx, booking)
+	if err != nil {
+		return models.BookingAlternatives{}, err
+	}
+
+	return models.BookingAlternatives{
+		Times:  times,
+		Tables: tables,
+	}, nil
+}
+
+// alternativeTimes ищет время, когда ни один стол брони не занят и не заблокирован. Сначала проверяется
+// тот же день: раньше и позже запрошенного времени с шагом в длительность брони, ближайшие варианты первыми.
+// Если их не хватило, предлагается то же время в ближайшие дни, начиная с соседних.
+func (s *BookingService) alternativeTimes(ctx context.Context, booking models.Booking, notBefore time.Time) ([]time.Time, error) {
+	times := []time.Time{}
+
+	// try добавляет t в варианты, если столы в это время свободны.
+	try := func(t time.Time) error {
+		if len(times) == maxAlternativeTimes || t.Before(notBefore) {
+			return nil
+		}
+
+		candidate := booking
+		candidate.BookingTime = t
+
+		free, err := s.tablesFree(ctx, booking.TableIDs, t, s.endsAt(candidate))
+		if err != nil {
+			return err
+		}
+
+		if free {
+			times = append(times, t)
+		}
+
+		return nil
+	}
+
+	step := s.endsAt(booking).Sub(booking.BookingTime)
+	for i := 1; step > 0 && len(times) < maxAlternativeTimes; i++ {
+		shift := time.Duration(i) * step
+		later, earlier := booking.BookingTime.Add(shift), booking.BookingTime.Add(-shift)
+
+		laterToday, earlierToday := sameDay(later, booking.BookingTime), sameDay(earlier, booking.BookingTime)
+		if !laterToday && !earlierToday {
+			break
+		}
+
+		if laterToday {
+			if err := try(later); err != nil {
+				return nil, err
+			}
+		}
+
+		if earlierToday {
+			if err := try(earlier); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for days := 1; days <= alternativeDaysRange && len(times) < maxAlternativeTimes; days++ {
+		for _, t := range []time.Time{booking.BookingTime.AddDate(0, 0, days), booking.BookingTime.AddDate(0, 0, -days)} {
+			if err := try(t); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	slices.SortFunc(times, func(a, b time.Time) int { return a.Compare(b) })
+
+	return times, nil
+}
+
+// sameDay сообщает, приходятся ли a и b на один календарный день.
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+
+	return ay == by && am == bm && ad == bd
+}
+
+// alternativeTables возвращает другие столы, свободные в запрошенное время, на которых хватает мест для компании
+// и есть обязательные атрибуты. Порядок - как при автоматическом подборе стола.
+func (s *BookingService) alternativeTables(ctx context.Context, booking models.Booking) ([]models.Table, error) {
+	free, err := s.freeTables(ctx, booking.BookingTime, s.endsAt(booking))
+	if err != nil {
+		return nil, err
+	}
+
+	free = slices.DeleteFunc(free, func(t models.Table) bool { return slices.Contains(booking.TableIDs, t.ID) })
+
+	tables := rankTables(free, booking)
+
+	if len(tables) > maxAlternativeTables {
+		tables = tables[:maxAlternativeTables]
+	}
+
+	return tables, nil
+}
+
+// tablesFree проверяет, что ни один из столов не занят бронью, не заблокирован и не стоит в закрытом зале на время визита [from, to).
+func (s *BookingService) tablesFree(ctx context.Context, tableIDs []int16, from, to time.Time) (bool, error) {
+	booked, err := s.postgres.GetBookedTables(ctx, from, to)
+	if err != nil {
+		return false, err
+	}
+
+	for _, id := range tableIDs {
+		if slices.Contains(booked, id) {
+			return false, nil
+		}
+	}
+
+	blocked, err := s.postgres.IsTableBlocked(ctx, tableIDs, from, to)
+	if err != nil {
+		return false, err
+	}
+
+	if blocked {
+		return false, nil
+	}
+
+	closed, err := s.closedTables(ctx, from, to)
+	if err != nil {
+		return false, err
+	}
+
+	for _, id := range tableIDs {
+		if slices.Contains(closed, id) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
@@ -2,22 +2,65 @@ package bookingsrv
 
 import (
 	"context"
+	"log/slog"
+	"slices"
 	"time"
 
+	"main_service/internal/config"
+	"main_service/internal/lib/logger/sl"
+	"main_service/internal/lib/pacing"
+	"main_service/internal/lib/payment"
+	"main_service/internal/lib/schedule"
+	"main_service/internal/lib/turntime"
 	"main_service/internal/models"
+	"main_service/internal/storage"
 	"main_service/internal/storage/redis"
 )
 
 type Postgres interface {
-	SaveBooking(ctx context.Context, booking models.Booking) error
-	DeleteBooking(ctx context.Context, tableId int16, bookingTime time.Time) error
+	SaveBooking(ctx context.Context, booking models.Booking, actor models.Actor) (int64, error)
+	DeleteBooking(ctx context.Context, tableId int16, bookingTime time.Time, actor models.Actor) (models.Booking, error)
 	IsBookingOwner(ctx context.Context, tableID int16, bookingTime time.Time, userID int64) (bool, error)
-	GetBookings(ctx context.Context, mode string) ([]models.BookingInfo, error)
+	GetBookings(ctx context.Context, filter models.BookingFilter) ([]models.BookingInfo, error)
+	IterateBookings(ctx context.Context, filter models.BookingFilter, fn func(models.BookingInfo) error) error
+	GetBookingHistory(ctx context.Context, bookingID int64) ([]models.BookingEvent, error)
+	GetBookingByID(ctx context.Context, bookingID int64) (models.BookingInfo, error)
+	UpdateBookingStatus(ctx context.Context, bookingID int64, from, to string, actor models.Actor) (models.Booking, error)
+	CheckInBooking(ctx context.Context, bookingID int64, actor models.Actor) (models.Booking, error)
+	CloseBooking(ctx context.Context, bookingID int64, status string, entry *models.LoyaltyTransaction, actor models.Actor) error
+	SaveSeries(ctx context.Context, series models.BookingSeries) (int64, error)
+	GetSeries(ctx context.Context, seriesID int64) (models.BookingSeries, error)
+	GetActiveSeriesBookings(ctx context.Context, seriesID int64, now time.Time) ([]models.Booking, error)
+	DeactivateSeries(ctx context.Context, seriesID int64) error
+	GetCombination(ctx context.Context, id int) (models.TableCombination, error)
+	IsTableBlocked(ctx context.Context, tableIDs []int16, from, to time.Time) (bool, error)
+	GetTables(ctx context.Context) ([]models.Table, error)
+	GetBookedTables(ctx context.Context, from, to time.Time) ([]int16, error)
+	GetBlockedTables(ctx context.Context, from, to time.Time) ([]int16, error)
+	GetZoneSchedules(ctx context.Context) ([]models.ZoneSchedule, error)
+	SavePayment(ctx context.Context, payment models.Payment) (int64, error)
+	GetPaymentByProviderID(ctx context.Context, providerID string) (models.Payment, error)
+	GetBookingPayment(ctx context.Context, bookingID int64) (models.Payment, error)
+	UpdatePaymentStatus(ctx context.Context, id int64, from, to string) error
+	GetExpiredPayments(ctx context.Context, now time.Time, limit int) ([]models.Payment, error)
+	GetPromoCode(ctx context.Context, code string) (models.PromoCode, error)
+	GetDishes(ctx context.Context, ids []int64) ([]models.Dish, error)
+	GetPreOrder(ctx context.Context, bookingID int64) ([]models.PreOrderItem, error)
+	ReplacePreOrder(ctx context.Context, bookingID int64, items []models.PreOrderItem) error
+	GetCustomerProfiles(ctx context.Context, userIDs []int64) (map[int64]models.CustomerProfile, error)
+	HasCustomerTag(ctx context.Context, userID int64, tag string) (bool, error)
+	GetActiveBookings(ctx context.Context, from time.Time) ([]models.Booking, error)
 }
 
 type Redis interface {
 	SaveBooking(ctx context.Context, booking redis.Booking) error
+	SaveTableBooking(ctx context.Context, booking redis.Booking) error
 	DeleteBooking(ctx context.Context, booking redis.Booking) error
+	ReservePacing(ctx context.Context, slot redis.PacingSlot) error
+	ReleasePacing(ctx context.Context, slot redis.PacingSlot) error
+	RestoreBooking(ctx context.Context, booking redis.Booking, checkUser bool) error
+	DeleteLegacyKeys(ctx context.Context) (int, error)
+	RestorePacing(ctx context.Context, slot redis.PacingSlot, bookings int) (bool, error)
 }
 
 type RabbitMQ interface {
@@ -25,58 +68,480 @@ type RabbitMQ interface {
 }
 
 type BookingService struct {
-	postgres Postgres
-	redis    Redis
-	rabbitmq RabbitMQ
+	postgres   Postgres
+	redis      Redis
+	rabbitmq   RabbitMQ
+	restaurant config.Restaurant
+	approval   config.Approval
+	pacing     config.Pacing
+	payments   payment.Provider
+	deposits   config.Deposits
+	loyalty    config.Loyalty
+	preOrders  config.PreOrders
+	feedback   config.Feedback
 }
 
-func NewBookingService(pg Postgres, r Redis, mq RabbitMQ) *BookingService {
+func NewBookingService(
+	pg Postgres,
+	r Redis,
+	mq RabbitMQ,
+	restaurant config.Restaurant,
+	approval config.Approval,
+	pacing config.Pacing,
+	payments payment.Provider,
+	deposits config.Deposits,
+	loyalty config.Loyalty,
+	preOrders config.PreOrders,
+	feedback config.Feedback,
+) *BookingService {
 	return &BookingService{
-		postgres: pg,
-		redis:    r,
-		rabbitmq: mq,
+		postgres:   pg,
+		redis:      r,
+		rabbitmq:   mq,
+		restaurant: restaurant,
+		approval:   approval,
+		pacing:     pacing,
+		payments:   payments,
+		deposits:   deposits,
+		loyalty:    loyalty,
+		preOrders:  preOrders,
+		feedback:   feedback,
 	}
 }
 
-func (s *BookingService) BookTable(ctx context.Context, booking models.Booking) error {
-	err := s.redis.SaveBooking(
-		ctx,
-		redis.Booking{
-			TableID: int64(booking.TableID),
-			Time:    booking.BookingTime,
-			UserID:  booking.UserID,
-		},
-	)
+// BookTable бронирует стол и возвращает сохранённую бронь. Большие компании и отдельные залы
+// бронируются в статусе pending_approval: стол уже занят, но бронь ждёт решения админа.
+// Если ни стол, ни комбинация не указаны, стол подбирается по атрибутам, которые просил гость.
+// Бронь не принимается, если кухня уже не успевает за гостями, приходящими в тот же интервал.
+// Если для брони нужен депозит, она сохраняется в статусе pending_payment и в booking.Deposit возвращается
+// ссылка на оплату. Админ узнает о такой брони только после оплаты.
+// Промокод проверяется до того, как стол будет занят, и засчитывается вместе с сохранением брони.
+// Гостям с тегом models.TagBlocked бронировать нельзя.
+func (s *BookingService) BookTable(ctx context.Context, booking models.Booking) (models.Booking, error) {
+	if err := s.checkCustomer(ctx, booking.UserID); err != nil {
+		return models.Booking{}, err
+	}
+
+	if booking.TableID == 0 && booking.CombinationID == 0 {
+		return s.bookAnyTable(ctx, booking)
+	}
+
+	booking, err := s.resolveTables(ctx, booking)
 	if err != nil {
-		return err
+		return models.Booking{}, err
+	}
+
+	if err := s.checkAttributes(ctx, &booking); err != nil {
+		return models.Booking{}, err
+	}
+
+	if err := s.checkBlocks(ctx, booking); err != nil {
+		return models.Booking{}, err
+	}
+
+	if err := s.checkZones(ctx, booking); err != nil {
+		return models.Booking{}, err
+	}
+
+	if err := s.checkPromoCode(ctx, &booking); err != nil {
+		return models.Booking{}, err
+	}
+
+	if err := s.checkPreOrder(ctx, &booking); err != nil {
+		return models.Booking{}, err
+	}
+
+	if s.depositRequired(booking) {
+		booking.Status = models.StatusPendingPayment
+	}
+
+	if err := s.redis.SaveBooking(ctx, s.redisBooking(booking)); err != nil {
+		return models.Booking{}, err
+	}
+
+	if err := s.redis.ReservePacing(ctx, s.pacingSlot(booking)); err != nil {
+		_ = s.redis.DeleteBooking(ctx, s.redisBooking(booking))
+
+		return models.Booking{}, err
+	}
+
+	if err := s.save(ctx, &booking); err != nil {
+		return models.Booking{}, err
+	}
+
+	if booking.Status == models.StatusPendingPayment {
+		if err := s.requestDeposit(ctx, &booking); err != nil {
+			return models.Booking{}, err
+		}
+
+		return booking, nil
 	}
 
-	if err := s.postgres.SaveBooking(ctx, booking); err != nil {
+	return booking, s.rabbitmq.SendNotification(ctx, booking)
+}
+
+// ApproveBooking подтверждает бронь, ожидающую решения админа, и уведомляет клиента.
+func (s *BookingService) ApproveBooking(ctx context.Context, bookingID int64, actor models.Actor) error {
+	booking, err := s.postgres.UpdateBookingStatus(ctx, bookingID, models.StatusPendingApproval, models.StatusConfirmed, actor)
+	if err != nil {
 		return err
 	}
 
-	return s.rabbitmq.SendNotification(ctx, booking)
+	return s.notifyDecision(ctx, booking, models.DecisionApproved, "")
 }
 
-func (s *BookingService) CancelBooking(ctx context.Context, booking redis.Booking) error {
-	if err := s.postgres.DeleteBooking(ctx, int16(booking.TableID), booking.Time); err != nil {
+// RejectBooking отклоняет бронь, ожидающую решения админа, освобождает стол, возвращает оплаченный депозит
+// и сообщает клиенту причину.
+func (s *BookingService) RejectBooking(ctx context.Context, bookingID int64, reason string, actor models.Actor) error {
+	booking, err := s.postgres.UpdateBookingStatus(ctx, bookingID, models.StatusPendingApproval, models.StatusRejected, actor)
+	if err != nil {
 		return err
 	}
 
-	if err := s.redis.DeleteBooking(ctx, booking); err != nil {
+	if err := s.release(ctx, booking); err != nil {
 		return err
 	}
 
+	if err := s.refundDeposit(ctx, booking.ID); err != nil {
+		return err
+	}
+
+	return s.notifyDecision(ctx, booking, models.DecisionRejected, reason)
+}
+
+// CancelBooking отменяет бронь от имени actor. Ключи в Redis снимаются по владельцу брони,
+// поэтому админ может отменить чужую бронь.
+// Оплаченный депозит возвращается, если бронь отменил админ или гость отменил её не позже чем за RefundWindow до визита.
+func (s *BookingService) CancelBooking(ctx context.Context, tableID int16, bookingTime time.Time, actor models.Actor) error {
+	booking, err := s.cancel(ctx, tableID, bookingTime, actor)
+	if err != nil {
+		return err
+	}
+
+	if actor.Role == models.RoleAdmin || time.Until(s.restaurant.InLocation(booking.BookingTime)) >= s.deposits.RefundWindow {
+		if err := s.refundDeposit(ctx, booking.ID); err != nil {
+			return err
+		}
+	}
+
 	return s.rabbitmq.SendNotification(
 		ctx,
 		models.Booking{
+			ID:          booking.ID,
 			UserID:      -1, // ! Если UserID == -1, то это отмена брони, в остальных случаях это новая бронь.
-			TableID:     int16(booking.TableID),
-			BookingTime: booking.Time,
+			TableID:     booking.TableID,
+			TableIDs:    booking.TableIDs,
+			BookingTime: booking.BookingTime,
 		},
 	)
 }
 
-func (s *BookingService) GetBookings(ctx context.Context, mode string) ([]models.BookingInfo, error) {
-	return s.postgres.GetBookings(ctx, mode)
+// resolveTables заполняет столы брони: для комбинации - все её столы, иначе - только TableID.
+// Заодно определяет статус, с которым бронь будет сохранена, и время её окончания.
+func (s *BookingService) resolveTables(ctx context.Context, booking models.Booking) (models.Booking, error) {
+	booking.EndsAt = s.endsAt(booking)
+
+	if booking.CombinationID == 0 {
+		booking.TableIDs = []int16{booking.TableID}
+		booking.Status = s.initialStatus(booking)
+
+		return booking, nil
+	}
+
+	combination, err := s.postgres.GetCombination(ctx, booking.CombinationID)
+	if err != nil {
+		return models.Booking{}, err
+	}
+
+	if booking.PartySize > combination.Capacity {
+		return models.Booking{}, storage.ErrPartyTooLarge
+	}
+
+	booking.TableID = combination.TableIDs[0]
+	booking.TableIDs = combination.TableIDs
+	booking.Status = s.initialStatus(booking)
+
+	return booking, nil
+}
+
+// checkAttributes заполняет атрибуты места, общие для всех столов брони, и возвращает
+// storage.ErrAttributesMismatch, если среди них нет обязательных.
+func (s *BookingService) checkAttributes(ctx context.Context, booking *models.Booking) error {
+	tables, err := s.postgres.GetTables(ctx)
+	if err != nil {
+		return err
+	}
+
+	booking.Attributes = seatingAttributes(tables, booking.TableIDs)
+	if !hasAll(booking.Attributes, booking.RequiredAttributes) {
+		return storage.ErrAttributesMismatch
+	}
+
+	return nil
+}
+
+// checkCustomer возвращает storage.ErrUserBlocked, если админы отметили гостя тегом models.TagBlocked.
+func (s *BookingService) checkCustomer(ctx context.Context, userID int64) error {
+	blocked, err := s.postgres.HasCustomerTag(ctx, userID, models.TagBlocked)
+	if err != nil {
+		return err
+	}
+
+	if blocked {
+		return storage.ErrUserBlocked
+	}
+
+	return nil
+}
+
+// checkBlocks возвращает storage.ErrTableIsBlocked, если хотя бы один стол брони заблокирован на время визита.
+func (s *BookingService) checkBlocks(ctx context.Context, booking models.Booking) error {
+	blocked, err := s.postgres.IsTableBlocked(
+		ctx,
+		booking.TableIDs,
+		booking.BookingTime,
+		booking.EndsAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	if blocked {
+		return storage.ErrTableIsBlocked
+	}
+
+	return nil
+}
+
+// endsAt возвращает время окончания визита по правилам длительности для размера компании и части дня.
+func (s *BookingService) endsAt(booking models.Booking) time.Time {
+	return booking.BookingTime.Add(turntime.Duration(s.restaurant, booking.PartySize, booking.BookingTime))
+}
+
+// checkZones возвращает storage.ErrZoneClosed, если зал хотя бы одного стола брони закрыт по расписанию на время визита.
+func (s *BookingService) checkZones(ctx context.Context, booking models.Booking) error {
+	closed, err := s.closedTables(ctx, booking.BookingTime, booking.EndsAt)
+	if err != nil {
+		return err
+	}
+
+	for _, tableID := range booking.TableIDs {
+		if slices.Contains(closed, tableID) {
+			return storage.ErrZoneClosed
+		}
+	}
+
+	return nil
+}
+
+// closedTables возвращает столы, залы которых закрыты по расписанию хотя бы на часть визита [from, to).
+func (s *BookingService) closedTables(ctx context.Context, from, to time.Time) ([]int16, error) {
+	tables, err := s.postgres.GetTables(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	schedules, err := s.postgres.GetZoneSchedules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return schedule.ClosedTables(tables, schedules, from, to), nil
+}
+
+// initialStatus возвращает статус новой брони: pending_approval, если компания больше порога
+// или хотя бы один стол находится в отдельном зале, иначе confirmed.
+func (s *BookingService) initialStatus(booking models.Booking) string {
+	if s.approval.PartySizeThreshold > 0 && int(booking.PartySize) > s.approval.PartySizeThreshold {
+		return models.StatusPendingApproval
+	}
+
+	for _, tableID := range booking.TableIDs {
+		if slices.Contains(s.approval.PrivateTables, tableID) {
+			return models.StatusPendingApproval
+		}
+	}
+
+	return models.StatusConfirmed
+}
+
+// notifyDecision отправляет клиенту уведомление о решении админа по брони.
+func (s *BookingService) notifyDecision(ctx context.Context, booking models.Booking, decision, reason string) error {
+	info, err := s.postgres.GetBookingByID(ctx, booking.ID)
+	if err != nil {
+		return err
+	}
+
+	booking.Decision = decision
+	booking.Reason = reason
+	booking.Email = info.Email
+
+	return s.rabbitmq.SendNotification(ctx, booking)
+}
+
+// save сохраняет бронь в Postgres после того, как стол занят в Redis, и записывает её id в booking.
+// Если сохранить не удалось, блокировка в Redis снимается, чтобы стол не остался занятым.
+func (s *BookingService) save(ctx context.Context, booking *models.Booking) error {
+	actor := models.Actor{ID: booking.UserID, Role: models.RoleCustomer}
+
+	id, err := s.postgres.SaveBooking(ctx, *booking, actor)
+	if err != nil {
+		_ = s.release(ctx, *booking)
+
+		return err
+	}
+	booking.ID = id
+
+	return nil
+}
+
+// cancel отменяет бронь в Postgres и снимает блокировку в Redis без отправки уведомления.
+func (s *BookingService) cancel(ctx context.Context, tableID int16, bookingTime time.Time, actor models.Actor) (models.Booking, error) {
+	booking, err := s.postgres.DeleteBooking(ctx, tableID, bookingTime, actor)
+	if err != nil {
+		return models.Booking{}, err
+	}
+
+	if err := s.release(ctx, booking); err != nil {
+		return models.Booking{}, err
+	}
+
+	return booking, nil
+}
+
+// release снимает блокировку столов брони в Redis и освобождает её место в интервале кухни.
+func (s *BookingService) release(ctx context.Context, booking models.Booking) error {
+	if err := s.redis.DeleteBooking(ctx, s.redisBooking(booking)); err != nil {
+		return err
+	}
+
+	return s.redis.ReleasePacing(ctx, s.pacingSlot(booking))
+}
+
+// GetBookings возвращает брони для админа вместе с профилями гостей, чтобы хостес сразу видела
+// постоянных гостей, их аллергии и заметки.
+func (s *BookingService) GetBookings(ctx context.Context, filter models.BookingFilter) ([]models.BookingInfo, error) {
+	bookings, err := s.postgres.GetBookings(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[int64]bool)
+	var userIDs []int64
+	for _, b := range bookings {
+		if !seen[b.UserID] {
+			seen[b.UserID] = true
+			userIDs = append(userIDs, b.UserID)
+		}
+	}
+
+	profiles, err := s.postgres.GetCustomerProfiles(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, b := range bookings {
+		if profile, ok := profiles[b.UserID]; ok {
+			bookings[i].Profile = &profile
+		}
+	}
+
+	return bookings, nil
+}
+
+// ExportBookings передаёт брони в fn по одной, чтобы выгрузка не держала весь список в памяти.
+func (s *BookingService) ExportBookings(ctx context.Context, filter models.BookingFilter, fn func(models.BookingInfo) error) error {
+	return s.postgres.IterateBookings(ctx, filter, fn)
+}
+
+// CloseBooking фиксирует итог визита по подтверждённой брони: гость пришёл (completed) или не пришёл (no_show).
+// За завершённый визит гостю начисляются баллы лояльности и приходит просьба оставить отзыв,
+// за неявку баллы списываются. Итог визита уже сохранён, когда отправляется просьба об отзыве,
+// поэтому если отправить её не удалось, ошибка только пишется в log.
+func (s *BookingService) CloseBooking(ctx context.Context, log *slog.Logger, bookingID int64, status string, actor models.Actor) error {
+	info, err := s.postgres.GetBookingByID(ctx, bookingID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.postgres.CloseBooking(ctx, bookingID, status, s.loyaltyEntry(info, status, actor), actor); err != nil {
+		return err
+	}
+
+	if status != models.StatusCompleted {
+		return nil
+	}
+
+	if err := s.requestFeedback(ctx, info); err != nil {
+		log.Error("failed to request feedback", slog.Int64("bookingID", bookingID), sl.Err(err))
+	}
+
+	return nil
+}
+
+// loyaltyEntry возвращает операцию с баллами за итог визита или nil, если баллы не меняются.
+func (s *BookingService) loyaltyEntry(booking models.BookingInfo, status string, actor models.Actor) *models.LoyaltyTransaction {
+	entry := &models.LoyaltyTransaction{CreatedBy: &actor.ID}
+
+	switch status {
+	case models.StatusCompleted:
+		entry.Kind = models.LoyaltyVisit
+		entry.Points = s.loyalty.PointsPerGuest * int(booking.PartySize)
+	case models.StatusNoShow:
+		entry.Kind = models.LoyaltyNoShow
+		entry.Points = -s.loyalty.NoShowPenalty
+	}
+
+	if entry.Points == 0 {
+		return nil
+	}
+
+	return entry
+}
+
+// CheckIn отмечает, что гости подтверждённой брони пришли и сели за стол.
+func (s *BookingService) CheckIn(ctx context.Context, bookingID int64, actor models.Actor) error {
+	_, err := s.postgres.CheckInBooking(ctx, bookingID, actor)
+
+	return err
+}
+
+// GetBookingHistory возвращает историю изменений брони.
+func (s *BookingService) GetBookingHistory(ctx context.Context, bookingID int64) ([]models.BookingEvent, error) {
+	return s.postgres.GetBookingHistory(ctx, bookingID)
+}
+
+// pacingSlot возвращает интервал кухни, на который приходится приход гостей брони, с лимитами его части дня.
+func (s *BookingService) pacingSlot(booking models.Booking) redis.PacingSlot {
+	bookingTime := s.restaurant.InLocation(booking.BookingTime)
+	start := pacing.Slot(bookingTime, s.pacing.Interval)
+	limit := pacing.Limit(s.pacing, s.restaurant.DayParts, bookingTime)
+
+	return redis.PacingSlot{
+		Start:       start,
+		End:         start.Add(s.pacing.Interval),
+		Covers:      int(booking.PartySize),
+		MaxCovers:   limit.MaxCovers,
+		MaxBookings: limit.MaxBookings,
+	}
+}
+
+// redisBooking собирает бронь для Redis: столы заняты до конца визита и ещё на время подготовки стола.
+// Redis хранит моменты времени, а брони, прочитанные из Postgres, приходят по часам ресторана с поясом UTC,
+// поэтому время переводится в пояс ресторана - иначе ключи сдвинулись бы на его смещение.
+func (s *BookingService) redisBooking(booking models.Booking) redis.Booking {
+	rb := redis.Booking{
+		TableID: int64(booking.TableID),
+		UserID:  booking.UserID,
+		Time:    s.restaurant.InLocation(booking.BookingTime),
+		Until:   s.restaurant.InLocation(booking.EndsAt).Add(s.restaurant.CleaningBuffer),
+	}
+
+	if len(booking.TableIDs) > 1 {
+		for _, tableID := range booking.TableIDs {
+			rb.TableIDs = append(rb.TableIDs, int64(tableID))
+		}
+	}
+
+	return rb
 }
@@ -0,0 +1,71 @@
+package bookingsrv
+
+import (
+	"context"
+	"time"
+
+	"main_service/internal/models"
+	"main_service/internal/storage/redis"
+)
+
+// RestoreCache восстанавливает в Redis занятость столов, ограничение "одна бронь на гостя" и счётчики
+// интервалов кухни по активным броням из Postgres. Вызывается при старте сервиса: Redis мог потерять данные,
+// а ключи в прежнем формате удаляются и пересоздаются. Уже записанные брони и существующие счётчики
+// не меняются, поэтому восстановление безопасно при работающих параллельно экземплярах.
+// Возвращает количество восстановленных броней.
+func (s *BookingService) RestoreCache(ctx context.Context) (int, error) {
+	if _, err := s.redis.DeleteLegacyKeys(ctx); err != nil {
+		return 0, err
+	}
+
+	now := s.restaurant.Local(time.Now())
+
+	bookings, err := s.postgres.GetActiveBookings(ctx, now.Add(-s.restaurant.CleaningBuffer))
+	if err != nil {
+		return 0, err
+	}
+
+	for i, booking := range bookings {
+		// Даты повторяющейся брони не ограничивают гостя одной бронью, как и при создании серии.
+		if err := s.redis.RestoreBooking(ctx, s.redisBooking(booking), booking.SeriesID == 0); err != nil {
+			return i, err
+		}
+	}
+
+	if err := s.restorePacing(ctx, bookings, now); err != nil {
+		return len(bookings), err
+	}
+
+	return len(bookings), nil
+}
+
+// restorePacing пересчитывает гостей и брони по интервалам кухни, которые ещё не закончились к now,
+// и создаёт недостающие счётчики.
+func (s *BookingService) restorePacing(ctx context.Context, bookings []models.Booking, now time.Time) error {
+	if s.pacing.Interval <= 0 {
+		return nil
+	}
+
+	slots := make(map[time.Time]redis.PacingSlot)
+	counts := make(map[time.Time]int)
+	for _, booking := range bookings {
+		slot := s.pacingSlot(booking)
+		if !slot.End.After(now) {
+			continue
+		}
+
+		if total, ok := slots[slot.Start]; ok {
+			slot.Covers += total.Covers
+		}
+		slots[slot.Start] = slot
+		counts[slot.Start]++
+	}
+
+	for start, slot := range slots {
+		if _, err := s.redis.RestorePacing(ctx, slot, counts[start]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
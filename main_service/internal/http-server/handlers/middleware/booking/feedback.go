@@ -0,0 +1,31 @@
+package bookingsrv
+
+import (
+	"context"
+	"time"
+
+	"main_service/internal/lib/feedback"
+	"main_service/internal/models"
+)
+
+// requestFeedback просит гостя оценить завершённый визит: письмо со ссылкой на отзыв, подписанной
+// секретом сервиса, уходит через сервис уведомлений. Без email гостя просить отзыв некуда.
+func (s *BookingService) requestFeedback(ctx context.Context, booking models.BookingInfo) error {
+	if booking.Email == "" {
+		return nil
+	}
+
+	expires := time.Now().Add(s.feedback.LinkTTL)
+
+	return s.rabbitmq.SendNotification(ctx, models.Booking{
+		ID:          booking.ID,
+		UserID:      booking.UserID,
+		TableID:     booking.TableID,
+		TableIDs:    booking.TableIDs,
+		BookingTime: booking.BookingTime,
+		PartySize:   booking.PartySize,
+		Status:      models.StatusCompleted,
+		Email:       booking.Email,
+		FeedbackURL: feedback.Link(s.feedback.PageURL, s.feedback.LinkSecret, booking.ID, expires),
+	})
+}
@@ -0,0 +1,280 @@
+package bookingsrv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"main_service/internal/lib/logger/sl"
+	"main_service/internal/lib/payment"
+	"main_service/internal/lib/turntime"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+)
+
+// expiryBatchSize - сколько просроченных платежей обрабатывается за один проход.
+const expiryBatchSize = 100
+
+// systemActor - от его имени сервис сам меняет статус брони, например когда депозит оплачен или не внесён вовремя.
+var systemActor = models.Actor{Role: models.RoleSystem}
+
+// depositRequired проверяет, нужен ли депозит для брони: для компании от порога или в дни и часть дня,
+// указанные в настройках, например в пятницу вечером.
+func (s *BookingService) depositRequired(booking models.Booking) bool {
+	if s.deposits.AmountPerGuest <= 0 {
+		return false
+	}
+
+	if s.deposits.PartySizeThreshold > 0 && int(booking.PartySize) >= s.deposits.PartySizeThreshold {
+		return true
+	}
+
+	weekday := strings.ToLower(booking.BookingTime.Weekday().String())
+	if !slices.Contains(s.deposits.Weekdays, weekday) {
+		return false
+	}
+
+	return s.deposits.DayPart == "" || turntime.DayPart(s.restaurant.DayParts, booking.BookingTime) == s.deposits.DayPart
+}
+
+// requestDeposit создаёт у провайдера платёж за депозит сохранённой брони и записывает его в booking.Deposit.
+// Если платёж создать не удалось, бронь отменяется и стол освобождается.
+func (s *BookingService) requestDeposit(ctx context.Context, booking *models.Booking) error {
+	p := models.Payment{
+		BookingID: booking.ID,
+		Amount:    s.deposits.AmountPerGuest * int64(booking.PartySize),
+		Currency:  s.deposits.Currency,
+		Status:    models.PaymentPending,
+		ExpiresAt: time.Now().Add(s.deposits.PaymentTimeout),
+	}
+
+	created, err := s.payments.CreatePayment(ctx, payment.Request{
+		BookingID:   booking.ID,
+		Amount:      p.Amount,
+		Currency:    p.Currency,
+		Description: fmt.Sprintf("Депозит за бронь №%d", booking.ID),
+		ExpiresAt:   p.ExpiresAt,
+	})
+	if err != nil {
+		_ = s.cancelUnpaid(ctx, booking.ID)
+
+		return err
+	}
+
+	p.ProviderID = created.ID
+	p.PaymentURL = created.URL
+
+	if p.ID, err = s.postgres.SavePayment(ctx, p); err != nil {
+		_ = s.cancelUnpaid(ctx, booking.ID)
+
+		return err
+	}
+	booking.Deposit = &p
+
+	return nil
+}
+
+// HandlePaymentCallback обрабатывает уведомление провайдера об оплате депозита. После успешной оплаты бронь
+// переходит в confirmed или pending_approval и админ получает уведомление о новой брони. Если бронь к этому
+// времени уже отменена, депозит возвращается. Неуспешная оплата отменяет бронь.
+// Повторные уведомления по тому же платежу ничего не меняют.
+func (s *BookingService) HandlePaymentCallback(ctx context.Context, body []byte, header http.Header) error {
+	cb, err := s.payments.ParseCallback(body, header)
+	if err != nil {
+		return err
+	}
+
+	p, err := s.postgres.GetPaymentByProviderID(ctx, cb.PaymentID)
+	if err != nil {
+		return err
+	}
+
+	switch cb.Status {
+	case payment.StatusSucceeded:
+		return s.confirmPayment(ctx, p)
+	case payment.StatusFailed:
+		if p.Status != models.PaymentPending {
+			return nil
+		}
+
+		if err := s.postgres.UpdatePaymentStatus(ctx, p.ID, models.PaymentPending, models.PaymentFailed); err != nil {
+			return err
+		}
+
+		return s.cancelUnpaid(ctx, p.BookingID)
+	}
+
+	return nil
+}
+
+// confirmPayment отмечает депозит оплаченным и переводит бронь из pending_payment в статус новой брони.
+func (s *BookingService) confirmPayment(ctx context.Context, p models.Payment) error {
+	switch p.Status {
+	case models.PaymentPaid, models.PaymentRefunded:
+		return nil
+	case models.PaymentExpired, models.PaymentFailed:
+		// Деньги пришли, когда бронь уже отменена.
+		return s.refund(ctx, p)
+	}
+
+	if err := s.postgres.UpdatePaymentStatus(ctx, p.ID, models.PaymentPending, models.PaymentPaid); err != nil {
+		return err
+	}
+	p.Status = models.PaymentPaid
+
+	info, err := s.postgres.GetBookingByID(ctx, p.BookingID)
+	if err != nil {
+		return err
+	}
+
+	booking := models.Booking{
+		ID:          info.ID,
+		UserID:      info.UserID,
+		TableID:     info.TableID,
+		TableIDs:    info.TableIDs,
+		BookingTime: info.BookingTime,
+		EndsAt:      info.EndsAt,
+		PartySize:   info.PartySize,
+		Notes:       info.Notes,
+		Occasion:    info.Occasion,
+		Allergens:   info.Allergens,
+		PromoCode:   info.PromoCode,
+	}
+	booking.Status = s.initialStatus(booking)
+
+	if _, err := s.postgres.UpdateBookingStatus(ctx, booking.ID, models.StatusPendingPayment, booking.Status, systemActor); err != nil {
+		if errors.Is(err, storage.ErrUnexpectedStatus) {
+			return s.refund(ctx, p)
+		}
+
+		return err
+	}
+
+	tables, err := s.postgres.GetTables(ctx)
+	if err != nil {
+		return err
+	}
+	booking.Attributes = seatingAttributes(tables, booking.TableIDs)
+
+	if booking.PreOrder, err = s.postgres.GetPreOrder(ctx, booking.ID); err != nil {
+		return err
+	}
+
+	if booking.PromoCode != "" {
+		code, err := s.postgres.GetPromoCode(ctx, booking.PromoCode)
+		if err != nil {
+			return err
+		}
+		booking.PromoDescription = code.Description
+	}
+
+	return s.rabbitmq.SendNotification(ctx, booking)
+}
+
+// ExpireUnpaid отменяет брони, депозит по которым не оплачен к now, и освобождает их столы.
+// Возвращает количество отменённых броней.
+func (s *BookingService) ExpireUnpaid(ctx context.Context, now time.Time) (int, error) {
+	payments, err := s.postgres.GetExpiredPayments(ctx, now, expiryBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, p := range payments {
+		// Оплата могла прийти параллельно - тогда платёж уже не pending и бронь остаётся.
+		err := s.postgres.UpdatePaymentStatus(ctx, p.ID, models.PaymentPending, models.PaymentExpired)
+		if errors.Is(err, storage.ErrPaymentStatus) {
+			continue
+		}
+		if err != nil {
+			return expired, err
+		}
+
+		if err := s.cancelUnpaid(ctx, p.BookingID); err != nil {
+			return expired, err
+		}
+		expired++
+	}
+
+	return expired, nil
+}
+
+// RunPaymentExpiry раз в interval отменяет неоплаченные брони, пока не отменён ctx.
+func (s *BookingService) RunPaymentExpiry(ctx context.Context, log *slog.Logger, interval time.Duration) {
+	const op = "bookingsrv.RunPaymentExpiry"
+
+	log = log.With(slog.String("op", op))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		n, err := s.ExpireUnpaid(ctx, time.Now())
+		if err != nil {
+			log.Error("failed to expire unpaid bookings", sl.Err(err))
+		}
+
+		if n > 0 {
+			log.Info("unpaid bookings expired", slog.Int("count", n))
+		}
+	}
+}
+
+// cancelUnpaid отменяет бронь, ожидающую оплаты, и освобождает стол. Если бронь уже не ждёт оплаты, ничего не делает.
+func (s *BookingService) cancelUnpaid(ctx context.Context, bookingID int64) error {
+	booking, err := s.postgres.UpdateBookingStatus(ctx, bookingID, models.StatusPendingPayment, models.StatusCancelled, systemActor)
+	if err != nil {
+		if errors.Is(err, storage.ErrUnexpectedStatus) {
+			return nil
+		}
+
+		return err
+	}
+
+	return s.release(ctx, booking)
+}
+
+// refundDeposit возвращает оплаченный депозит брони. Если депозита не было или он не оплачен, ничего не делает.
+func (s *BookingService) refundDeposit(ctx context.Context, bookingID int64) error {
+	p, err := s.postgres.GetBookingPayment(ctx, bookingID)
+	if err != nil {
+		if errors.Is(err, storage.ErrPaymentNotFound) {
+			return nil
+		}
+
+		return err
+	}
+
+	if p.Status != models.PaymentPaid {
+		return nil
+	}
+
+	return s.refund(ctx, p)
+}
+
+// refund возвращает гостю всю сумму платежа. Статус меняется до обращения к провайдеру,
+// чтобы параллельный запрос не вернул деньги второй раз.
+func (s *BookingService) refund(ctx context.Context, p models.Payment) error {
+	if err := s.postgres.UpdatePaymentStatus(ctx, p.ID, p.Status, models.PaymentRefunded); err != nil {
+		return err
+	}
+
+	if err := s.payments.Refund(ctx, p.ProviderID, p.Amount); err != nil {
+		_ = s.postgres.UpdatePaymentStatus(ctx, p.ID, models.PaymentRefunded, p.Status)
+
+		return err
+	}
+
+	return nil
+}
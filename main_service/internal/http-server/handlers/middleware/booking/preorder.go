@@ -0,0 +1,137 @@
+package bookingsrv
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"main_service/internal/models"
+	"main_service/internal/storage"
+)
+
+// GetPreOrder возвращает предзаказ брони. Гость видит только предзаказ своей брони.
+func (s *BookingService) GetPreOrder(ctx context.Context, bookingID int64, actor models.Actor) ([]models.PreOrderItem, error) {
+	if _, err := s.preOrderBooking(ctx, bookingID, actor); err != nil {
+		return nil, err
+	}
+
+	return s.postgres.GetPreOrder(ctx, bookingID)
+}
+
+// UpdatePreOrder заменяет предзаказ активной брони и уведомляет кухню об изменении.
+// Гость может менять предзаказ своей брони не позже чем за Cutoff до визита, админ - в любое время.
+func (s *BookingService) UpdatePreOrder(ctx context.Context, bookingID int64, items []models.PreOrderItem, actor models.Actor) ([]models.PreOrderItem, error) {
+	info, err := s.preOrderBooking(ctx, bookingID, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	if actor.Role != models.RoleAdmin && time.Until(s.restaurant.InLocation(info.BookingTime)) < s.preOrders.Cutoff {
+		return nil, storage.ErrPreOrderClosed
+	}
+
+	items, err = s.preOrderItems(ctx, items, info.PartySize)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.postgres.ReplacePreOrder(ctx, bookingID, items); err != nil {
+		return nil, err
+	}
+
+	if items == nil {
+		items = []models.PreOrderItem{}
+	}
+
+	// Об оплачиваемой брони админ узнает после оплаты, уже с актуальным предзаказом.
+	if info.Status == models.StatusPendingPayment {
+		return items, nil
+	}
+
+	return items, s.rabbitmq.SendNotification(ctx, models.Booking{
+		ID:              info.ID,
+		UserID:          info.UserID,
+		TableID:         info.TableID,
+		TableIDs:        info.TableIDs,
+		BookingTime:     info.BookingTime,
+		PartySize:       info.PartySize,
+		PreOrder:        items,
+		PreOrderChanged: true,
+	})
+}
+
+// preOrderBooking возвращает бронь, предзаказ которой запрашивает actor.
+// Чужая бронь для гостя выглядит как несуществующая.
+func (s *BookingService) preOrderBooking(ctx context.Context, bookingID int64, actor models.Actor) (models.BookingInfo, error) {
+	info, err := s.postgres.GetBookingByID(ctx, bookingID)
+	if err != nil {
+		return models.BookingInfo{}, err
+	}
+
+	if actor.Role != models.RoleAdmin && info.UserID != actor.ID {
+		return models.BookingInfo{}, storage.ErrBookingNotFound
+	}
+
+	return info, nil
+}
+
+// checkPreOrder проверяет предзаказ новой брони и заполняет названия и цены блюд.
+func (s *BookingService) checkPreOrder(ctx context.Context, booking *models.Booking) error {
+	items, err := s.preOrderItems(ctx, booking.PreOrder, booking.PartySize)
+	if err != nil {
+		return err
+	}
+	booking.PreOrder = items
+
+	return nil
+}
+
+// preOrderItems объединяет повторяющиеся блюда, проверяет, что все они есть в меню и доступны,
+// а порций не больше MaxItemsPerGuest на гостя, и заполняет названия и текущие цены.
+func (s *BookingService) preOrderItems(ctx context.Context, items []models.PreOrderItem, partySize int16) ([]models.PreOrderItem, error) {
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	merged := make([]models.PreOrderItem, 0, len(items))
+	total := 0
+	for _, item := range items {
+		total += item.Quantity
+
+		if i := slices.IndexFunc(merged, func(m models.PreOrderItem) bool { return m.DishID == item.DishID }); i >= 0 {
+			merged[i].Quantity += item.Quantity
+			continue
+		}
+		merged = append(merged, models.PreOrderItem{DishID: item.DishID, Quantity: item.Quantity})
+	}
+
+	if total > int(partySize)*s.preOrders.MaxItemsPerGuest {
+		return nil, storage.ErrPreOrderTooLarge
+	}
+
+	ids := make([]int64, 0, len(merged))
+	for _, item := range merged {
+		ids = append(ids, item.DishID)
+	}
+
+	dishes, err := s.postgres.GetDishes(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, item := range merged {
+		idx := slices.IndexFunc(dishes, func(d models.Dish) bool { return d.ID == item.DishID })
+		if idx < 0 {
+			return nil, storage.ErrDishNotFound
+		}
+
+		if !dishes[idx].IsAvailable {
+			return nil, storage.ErrDishUnavailable
+		}
+
+		merged[i].Name = dishes[idx].Name
+		merged[i].Price = dishes[idx].Price
+	}
+
+	return merged, nil
+}
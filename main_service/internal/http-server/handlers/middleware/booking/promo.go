@@ -0,0 +1,35 @@
+package bookingsrv
+
+import (
+	"context"
+
+	"main_service/internal/lib/promo"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+)
+
+// checkPromoCode проверяет промокод брони и заполняет его описание для уведомления админа.
+// Использование засчитывается при сохранении брони, в той же транзакции.
+func (s *BookingService) checkPromoCode(ctx context.Context, booking *models.Booking) error {
+	if booking.PromoCode == "" {
+		return nil
+	}
+
+	code, err := s.postgres.GetPromoCode(ctx, promo.Normalize(booking.PromoCode))
+	if err != nil {
+		return err
+	}
+
+	if !promo.Applies(code, booking.BookingTime) {
+		return storage.ErrPromoCodeInvalid
+	}
+
+	if promo.Exhausted(code) {
+		return storage.ErrPromoCodeExhausted
+	}
+
+	booking.PromoCode = code.Code
+	booking.PromoDescription = code.Description
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package bookingsrv
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"main_service/internal/lib/schedule"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+)
+
+// maxAssignAttempts - сколько подходящих столов пробовать, если выбранный успели занять параллельно.
+const maxAssignAttempts = 3
+
+// bookAnyTable подбирает стол под пожелания гостя и бронирует его.
+// Если стол успели занять, пробуется следующий по рейтингу.
+func (s *BookingService) bookAnyTable(ctx context.Context, booking models.Booking) (models.Booking, error) {
+	free, err := s.freeTables(ctx, booking.BookingTime, s.endsAt(booking))
+	if err != nil {
+		return models.Booking{}, err
+	}
+
+	candidates := rankTables(free, booking)
+	if len(candidates) == 0 {
+		return models.Booking{}, storage.ErrNoSuitableTable
+	}
+
+	for _, t := range candidates[:min(len(candidates), maxAssignAttempts)] {
+		b := booking
+		b.TableID = t.ID
+
+		booked, err := s.BookTable(ctx, b)
+		if errors.Is(err, storage.ErrTableIsBooked) {
+			continue
+		}
+
+		return booked, err
+	}
+
+	return models.Booking{}, storage.ErrTableIsBooked
+}
+
+// freeTables возвращает столы, которые не заняты другими бронями, не заблокированы на время визита [from, to)
+// и стоят в открытых по расписанию залах.
+func (s *BookingService) freeTables(ctx context.Context, from, to time.Time) ([]models.Table, error) {
+	all, err := s.postgres.GetTables(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	booked, err := s.postgres.GetBookedTables(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	blocked, err := s.postgres.GetBlockedTables(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	schedules, err := s.postgres.GetZoneSchedules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	free := make([]models.Table, 0, len(all))
+	for _, t := range all {
+		if slices.Contains(booked, t.ID) ||
+			slices.Contains(blocked, t.ID) ||
+			!schedule.IsOpen(schedules, t.Zone, from, to) {
+			continue
+		}
+		free = append(free, t)
+	}
+
+	return free, nil
+}
+
+// rankTables оставляет столы, на которых хватает мест и есть все обязательные атрибуты, и сортирует их:
+// сначала больше совпадений с желательными атрибутами, затем меньше лишних мест.
+func rankTables(tables []models.Table, booking models.Booking) []models.Table {
+	ranked := make([]models.Table, 0, len(tables))
+	for _, t := range tables {
+		if t.Seats >= booking.PartySize && hasAll(t.Attributes, booking.RequiredAttributes) {
+			ranked = append(ranked, t)
+		}
+	}
+
+	slices.SortStableFunc(ranked, func(a, b models.Table) int {
+		if d := countMatches(b.Attributes, booking.PreferredAttributes) - countMatches(a.Attributes, booking.PreferredAttributes); d != 0 {
+			return d
+		}
+
+		return int(a.Seats) - int(b.Seats)
+	})
+
+	return ranked
+}
+
+// seatingAttributes возвращает атрибуты, которые есть у всех столов tableIDs.
+func seatingAttributes(tables []models.Table, tableIDs []int16) []string {
+	var common []string
+	for i, id := range tableIDs {
+		idx := slices.IndexFunc(tables, func(t models.Table) bool { return t.ID == id })
+		if idx < 0 {
+			return nil
+		}
+
+		if i == 0 {
+			common = slices.Clone(tables[idx].Attributes)
+			continue
+		}
+
+		common = slices.DeleteFunc(common, func(a string) bool {
+			return !slices.Contains(tables[idx].Attributes, a)
+		})
+	}
+
+	return common
+}
+
+func hasAll(attributes, required []string) bool {
+	return countMatches(attributes, required) == len(required)
+}
+
+func countMatches(attributes, wanted []string) int {
+	n := 0
+	for _, a := range wanted {
+		if slices.Contains(attributes, a) {
+			n++
+		}
+	}
+
+	return n
+}
@@ -0,0 +1,217 @@
+package bookingsrv
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"main_service/internal/models"
+	"main_service/internal/storage"
+)
+
+// MaxSeriesOccurrences ограничивает количество дат в одной повторяющейся брони.
+const MaxSeriesOccurrences = 52
+
+// Причины, по которым дата серии не была забронирована.
+const (
+	ConflictTableIsBooked  = "table_is_booked"
+	ConflictTableIsBlocked = "table_is_blocked"
+	ConflictPastDate       = "past_date"
+	ConflictZoneClosed     = "zone_closed"
+	ConflictPacingLimit    = "pacing_limit"
+	// ConflictDepositRequired - на эту дату нужен депозит. Серия не принимает оплату,
+	// такую дату гость бронирует отдельно и вносит депозит.
+	ConflictDepositRequired = "deposit_required"
+)
+
+// SeriesOccurrences возвращает даты серии: начиная с start каждые intervalWeeks недель,
+// пока не набрано count дат (если count > 0) и дата не позже until (если until не нулевой).
+func SeriesOccurrences(start time.Time, intervalWeeks, count int, until time.Time) []time.Time {
+	var times []time.Time
+
+	for i := 0; i < MaxSeriesOccurrences; i++ {
+		if count > 0 && i >= count {
+			break
+		}
+
+		t := start.AddDate(0, 0, 7*intervalWeeks*i)
+		if !until.IsZero() && t.After(until) {
+			break
+		}
+
+		times = append(times, t)
+	}
+
+	return times
+}
+
+// BookSeries создаёт повторяющуюся бронь: каждая дата бронируется отдельно,
+// занятые даты и даты, на которые нужен депозит, пропускаются и возвращаются в Conflicts.
+// Администратор получает одно уведомление на всю серию. Гостям с тегом models.TagBlocked серию не бронируем.
+func (s *BookingService) BookSeries(
+	ctx context.Context,
+	booking models.Booking,
+	intervalWeeks int,
+	times []time.Time,
+) (models.SeriesResult, error) {
+	if err := s.checkCustomer(ctx, booking.UserID); err != nil {
+		return models.SeriesResult{}, err
+	}
+
+	booking, err := s.resolveTables(ctx, booking)
+	if err != nil {
+		return models.SeriesResult{}, err
+	}
+
+	if err := s.checkAttributes(ctx, &booking); err != nil {
+		return models.SeriesResult{}, err
+	}
+
+	seriesID, err := s.postgres.SaveSeries(ctx, models.BookingSeries{
+		UserID:        booking.UserID,
+		TableID:       booking.TableID,
+		StartsAt:      booking.BookingTime,
+		IntervalWeeks: intervalWeeks,
+		Occurrences:   len(times),
+	})
+	if err != nil {
+		return models.SeriesResult{}, err
+	}
+
+	result := models.SeriesResult{
+		SeriesID:  seriesID,
+		Booked:    []time.Time{},
+		Conflicts: []models.SeriesConflict{},
+	}
+
+	var first models.Booking
+	for _, t := range times {
+		occurrence := booking
+		occurrence.BookingTime = t
+		occurrence.EndsAt = s.endsAt(occurrence)
+		occurrence.SeriesID = seriesID
+
+		if s.depositRequired(occurrence) {
+			result.Conflicts = append(result.Conflicts, models.SeriesConflict{BookingTime: t, Reason: ConflictDepositRequired})
+			continue
+		}
+
+		if err := s.checkBlocks(ctx, occurrence); err != nil {
+			if errors.Is(err, storage.ErrTableIsBlocked) {
+				result.Conflicts = append(result.Conflicts, models.SeriesConflict{BookingTime: t, Reason: ConflictTableIsBlocked})
+				continue
+			}
+
+			return result, err
+		}
+
+		if err := s.checkZones(ctx, occurrence); err != nil {
+			if errors.Is(err, storage.ErrZoneClosed) {
+				result.Conflicts = append(result.Conflicts, models.SeriesConflict{BookingTime: t, Reason: ConflictZoneClosed})
+				continue
+			}
+
+			return result, err
+		}
+
+		err := s.redis.SaveTableBooking(ctx, s.redisBooking(occurrence))
+		if err != nil {
+			switch {
+			case errors.Is(err, storage.ErrTableIsBooked):
+				result.Conflicts = append(result.Conflicts, models.SeriesConflict{BookingTime: t, Reason: ConflictTableIsBooked})
+				continue
+			case errors.Is(err, storage.ErrPastDate):
+				result.Conflicts = append(result.Conflicts, models.SeriesConflict{BookingTime: t, Reason: ConflictPastDate})
+				continue
+			default:
+				return result, err
+			}
+		}
+
+		if err := s.redis.ReservePacing(ctx, s.pacingSlot(occurrence)); err != nil {
+			_ = s.redis.DeleteBooking(ctx, s.redisBooking(occurrence))
+
+			if errors.Is(err, storage.ErrPacingLimit) {
+				result.Conflicts = append(result.Conflicts, models.SeriesConflict{BookingTime: t, Reason: ConflictPacingLimit})
+				continue
+			}
+
+			return result, err
+		}
+
+		if err := s.save(ctx, &occurrence); err != nil {
+			if errors.Is(err, storage.ErrTableIsBooked) {
+				result.Conflicts = append(result.Conflicts, models.SeriesConflict{BookingTime: t, Reason: ConflictTableIsBooked})
+				continue
+			}
+
+			return result, err
+		}
+
+		if len(result.Booked) == 0 {
+			first = occurrence
+		}
+		result.Booked = append(result.Booked, t)
+	}
+
+	if len(result.Booked) == 0 {
+		return result, s.postgres.DeactivateSeries(ctx, seriesID)
+	}
+
+	first.Occurrences = len(result.Booked)
+
+	return result, s.rabbitmq.SendNotification(ctx, first)
+}
+
+// GetSeries возвращает повторяющуюся бронь.
+func (s *BookingService) GetSeries(ctx context.Context, seriesID int64) (models.BookingSeries, error) {
+	return s.postgres.GetSeries(ctx, seriesID)
+}
+
+// CancelSeries отменяет все будущие даты серии и возвращает количество отменённых броней.
+// Прошедшие даты остаются в истории как есть.
+func (s *BookingService) CancelSeries(ctx context.Context, seriesID int64, actor models.Actor) (int, error) {
+	bookings, err := s.postgres.GetActiveSeriesBookings(ctx, seriesID, s.restaurant.Local(time.Now()))
+	if err != nil {
+		return 0, err
+	}
+
+	var first models.Booking
+	cancelled := 0
+	for _, b := range bookings {
+		booking, err := s.cancel(ctx, b.TableID, b.BookingTime, actor)
+		if err != nil {
+			// Дату могли отменить отдельно, пока мы шли по списку.
+			if errors.Is(err, storage.ErrBookingNotFound) {
+				continue
+			}
+
+			return cancelled, err
+		}
+
+		if cancelled == 0 {
+			first = booking
+		}
+		cancelled++
+	}
+
+	if err := s.postgres.DeactivateSeries(ctx, seriesID); err != nil {
+		return cancelled, err
+	}
+
+	if cancelled == 0 {
+		return 0, nil
+	}
+
+	return cancelled, s.rabbitmq.SendNotification(
+		ctx,
+		models.Booking{
+			UserID:      -1,
+			TableID:     first.TableID,
+			TableIDs:    first.TableIDs,
+			BookingTime: first.BookingTime,
+			SeriesID:    seriesID,
+			Occurrences: cancelled,
+		},
+	)
+}
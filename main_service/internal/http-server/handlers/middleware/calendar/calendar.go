@@ -0,0 +1,150 @@
+package calendarsrv
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"main_service/internal/config"
+	"main_service/internal/lib/ical"
+	"main_service/internal/models"
+)
+
+// refreshInterval - как часто календарные приложения должны перечитывать ленту.
+const refreshInterval = 15 * time.Minute
+
+type Postgres interface {
+	SaveCalendarToken(ctx context.Context, userID int64, tokenHash string) error
+	UserByCalendarToken(ctx context.Context, tokenHash string) (int64, error)
+	GetBookings(ctx context.Context, filter models.BookingFilter) ([]models.BookingInfo, error)
+	GetBookingByID(ctx context.Context, bookingID int64) (models.BookingInfo, error)
+}
+
+type CalendarService struct {
+	postgres   Postgres
+	restaurant config.Restaurant
+}
+
+func NewCalendarService(pg Postgres, restaurant config.Restaurant) *CalendarService {
+	return &CalendarService{
+		postgres:   pg,
+		restaurant: restaurant,
+	}
+}
+
+// IssueToken выпускает новый секретный токен ленты для пользователя. Старый токен перестаёт работать.
+// В базе хранится только хэш токена.
+func (s *CalendarService) IssueToken(ctx context.Context, userID int64) (string, error) {
+	const op = "calendarsrv.IssueToken"
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	token := hex.EncodeToString(buf)
+
+	if err := s.postgres.SaveCalendarToken(ctx, userID, hashToken(token)); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// TokenOwner возвращает id пользователя, которому выдан токен.
+func (s *CalendarService) TokenOwner(ctx context.Context, token string) (int64, error) {
+	return s.postgres.UserByCalendarToken(ctx, hashToken(token))
+}
+
+// Feed возвращает календарь активных броней начиная с сегодняшнего дня по часам ресторана.
+func (s *CalendarService) Feed(ctx context.Context) (ical.Calendar, error) {
+	now := s.restaurant.Local(time.Now())
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	bookings, err := s.postgres.GetBookings(ctx, models.BookingFilter{
+		Mode: "active",
+		From: &today,
+	})
+	if err != nil {
+		return ical.Calendar{}, err
+	}
+
+	cal := ical.Calendar{
+		Name:            "Брони ресторана",
+		RefreshInterval: refreshInterval,
+		Events:          make([]ical.Event, 0, len(bookings)),
+	}
+
+	for _, b := range bookings {
+		cal.Events = append(cal.Events, s.event(b))
+	}
+
+	return cal, nil
+}
+
+// BookingCalendar возвращает календарь с одной бронью.
+func (s *CalendarService) BookingCalendar(ctx context.Context, bookingID int64) (ical.Calendar, models.BookingInfo, error) {
+	booking, err := s.postgres.GetBookingByID(ctx, bookingID)
+	if err != nil {
+		return ical.Calendar{}, models.BookingInfo{}, err
+	}
+
+	cal := ical.Calendar{
+		Events: []ical.Event{s.event(booking)},
+	}
+
+	return cal, booking, nil
+}
+
+func (s *CalendarService) event(b models.BookingInfo) ical.Event {
+	table := fmt.Sprintf("Стол %d", b.TableID)
+	if len(b.TableIDs) > 1 {
+		ids := make([]string, 0, len(b.TableIDs))
+		for _, id := range b.TableIDs {
+			ids = append(ids, fmt.Sprint(id))
+		}
+		table = "Столы " + strings.Join(ids, "+")
+	}
+
+	description := fmt.Sprintf("Бронь №%d\nГость: %s %s (%s)\n%s\nГостей: %d",
+		b.ID, b.FirstName, b.LastName, b.Email, table, b.PartySize)
+
+	if b.Occasion != "" {
+		description += "\nПовод: " + b.Occasion
+	}
+
+	if len(b.Allergens) > 0 {
+		description += "\nАллергены: " + strings.Join(b.Allergens, ", ")
+	}
+
+	if b.Notes != "" {
+		description += "\nПожелания: " + b.Notes
+	}
+
+	summary := fmt.Sprintf("%s: %s %s, гостей: %d", table, b.FirstName, b.LastName, b.PartySize)
+	if b.Status == models.StatusPendingApproval {
+		summary += " (ждёт подтверждения)"
+	}
+	if b.Status == models.StatusPendingPayment {
+		summary += " (ждёт оплаты депозита)"
+	}
+
+	return ical.Event{
+		UID:         fmt.Sprintf("booking-%d@restaurant", b.ID),
+		Start:       s.restaurant.InLocation(b.BookingTime),
+		End:         s.restaurant.InLocation(b.EndsAt),
+		Summary:     summary,
+		Description: description,
+		Location:    table,
+	}
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,55 @@
+package customersrv
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"main_service/internal/models"
+)
+
+type Postgres interface {
+	GetCustomerProfile(ctx context.Context, userID int64) (models.CustomerProfile, error)
+	SaveCustomerProfile(ctx context.Context, profile models.CustomerProfile, updatedBy int64) error
+}
+
+type CustomerService struct {
+	postgres Postgres
+}
+
+func NewCustomerService(pg Postgres) *CustomerService {
+	return &CustomerService{postgres: pg}
+}
+
+// GetProfile возвращает профиль гостя: статистику визитов, теги и заметки.
+func (s *CustomerService) GetProfile(ctx context.Context, userID int64) (models.CustomerProfile, error) {
+	return s.postgres.GetCustomerProfile(ctx, userID)
+}
+
+// UpdateProfile заменяет теги и заметки о госте и возвращает обновлённый профиль.
+// Теги хранятся в нижнем регистре без повторов, чтобы "VIP" и "vip" были одним тегом.
+func (s *CustomerService) UpdateProfile(ctx context.Context, userID int64, tags []string, notes string, adminID int64) (models.CustomerProfile, error) {
+	profile := models.CustomerProfile{
+		UserID: userID,
+		Tags:   normalizeTags(tags),
+		Notes:  strings.TrimSpace(notes),
+	}
+
+	if err := s.postgres.SaveCustomerProfile(ctx, profile, adminID); err != nil {
+		return models.CustomerProfile{}, err
+	}
+
+	return s.postgres.GetCustomerProfile(ctx, userID)
+}
+
+func normalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && !slices.Contains(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+
+	return normalized
+}
@@ -0,0 +1,154 @@
+package eventsrv
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"main_service/internal/lib/logger/sl"
+	"main_service/internal/models"
+)
+
+const (
+	// pollInterval - как часто брокер проверяет новые события в истории броней.
+	pollInterval = time.Second
+	// pageSize - сколько событий читается из базы за один запрос.
+	pageSize = 500
+	// subscriberBuffer - сколько событий может ждать отправки одному подписчику.
+	// Если подписчик не успевает, он отключается и переподключается с Last-Event-ID.
+	subscriberBuffer = 64
+)
+
+type Postgres interface {
+	GetBookingEventsAfter(ctx context.Context, after models.EventCursor, limit int) ([]models.BookingEvent, error)
+	GetLastBookingEventCursor(ctx context.Context) (models.EventCursor, error)
+	GetBookingEventCursor(ctx context.Context, eventID int64) (models.EventCursor, error)
+}
+
+// Broker раздаёт подписчикам события из истории броней. Источник - таблица booking_events,
+// в которую BookingService записывает каждое создание, отмену и изменение брони, поэтому
+// id события подходит для Last-Event-ID и события не теряются между экземплярами сервиса.
+//
+// id выдаётся при вставке, а транзакции коммитятся в любом порядке, поэтому лента идёт
+// в порядке (транзакция, id) и отстаёт до завершения самой старой открытой транзакции в базе.
+// Так событие, закоммиченное позже соседа с большим id, не пропускается.
+type Broker struct {
+	log      *slog.Logger
+	postgres Postgres
+
+	mu          sync.Mutex
+	subscribers map[chan models.BookingEvent]struct{}
+}
+
+func NewBroker(log *slog.Logger, pg Postgres) *Broker {
+	return &Broker{
+		log:         log,
+		postgres:    pg,
+		subscribers: make(map[chan models.BookingEvent]struct{}),
+	}
+}
+
+// Run опрашивает историю броней и рассылает новые события, пока не отменён ctx.
+func (b *Broker) Run(ctx context.Context) {
+	const op = "eventsrv.Broker.Run"
+
+	log := b.log.With(slog.String("op", op))
+
+	cursor, err := b.postgres.GetLastBookingEventCursor(ctx)
+	if err != nil {
+		log.Error("failed to get last booking event cursor", sl.Err(err))
+	}
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		for {
+			events, err := b.postgres.GetBookingEventsAfter(ctx, cursor, pageSize)
+			if err != nil {
+				log.Error("failed to poll booking events", sl.Err(err))
+				break
+			}
+
+			for _, e := range events {
+				b.broadcast(e)
+				cursor = e.Cursor()
+			}
+
+			if len(events) < pageSize {
+				break
+			}
+		}
+	}
+}
+
+// Subscribe подписывает на новые события. Канал закрывается, если подписчик отстал;
+// unsubscribe нужно вызвать, когда события больше не нужны.
+func (b *Broker) Subscribe() (events <-chan models.BookingEvent, unsubscribe func()) {
+	ch := make(chan models.BookingEvent, subscriberBuffer)
+
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+
+	return ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		if _, ok := b.subscribers[ch]; ok {
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// Cursor возвращает позицию события eventID в ленте, 0 - начало ленты.
+func (b *Broker) Cursor(ctx context.Context, eventID int64) (models.EventCursor, error) {
+	if eventID == 0 {
+		return models.EventCursor{}, nil
+	}
+
+	return b.postgres.GetBookingEventCursor(ctx, eventID)
+}
+
+// Replay передаёт в fn все события после позиции after, чтобы переподключившийся клиент получил пропущенное.
+func (b *Broker) Replay(ctx context.Context, after models.EventCursor, fn func(models.BookingEvent) error) error {
+	for {
+		events, err := b.postgres.GetBookingEventsAfter(ctx, after, pageSize)
+		if err != nil {
+			return err
+		}
+
+		for _, e := range events {
+			if err := fn(e); err != nil {
+				return err
+			}
+			after = e.Cursor()
+		}
+
+		if len(events) < pageSize {
+			return nil
+		}
+	}
+}
+
+func (b *Broker) broadcast(e models.BookingEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- e:
+		default:
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package feedbacksrv
+
+import (
+	"context"
+	"time"
+
+	"main_service/internal/config"
+	"main_service/internal/lib/feedback"
+	"main_service/internal/models"
+)
+
+type Postgres interface {
+	SaveReview(ctx context.Context, review models.Review) (models.Review, error)
+}
+
+type FeedbackService struct {
+	postgres Postgres
+	feedback config.Feedback
+}
+
+func NewFeedbackService(pg Postgres, feedback config.Feedback) *FeedbackService {
+	return &FeedbackService{
+		postgres: pg,
+		feedback: feedback,
+	}
+}
+
+// SubmitReview сохраняет отзыв гостя, пришедший по ссылке из письма. Ссылка заменяет авторизацию:
+// её подпись подтверждает, что отзыв оставляет гость этой брони. По одной брони принимается один отзыв.
+func (s *FeedbackService) SubmitReview(
+	ctx context.Context,
+	review models.Review,
+	expires time.Time,
+	signature string,
+) (models.Review, error) {
+	if err := feedback.Verify(s.feedback.LinkSecret, review.BookingID, expires, signature, time.Now()); err != nil {
+		return models.Review{}, err
+	}
+
+	return s.postgres.SaveReview(ctx, review)
+}
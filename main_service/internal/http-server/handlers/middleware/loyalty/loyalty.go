@@ -0,0 +1,38 @@
+package loyaltysrv
+
+import (
+	"context"
+
+	"main_service/internal/models"
+)
+
+// historyLimit - сколько последних операций с баллами показывается вместе с балансом.
+const historyLimit = 100
+
+type Postgres interface {
+	GetLoyaltyAccount(ctx context.Context, userID int64, limit int) (models.LoyaltyAccount, error)
+	SaveLoyaltyAdjustment(ctx context.Context, entry models.LoyaltyTransaction) (models.LoyaltyTransaction, error)
+}
+
+type LoyaltyService struct {
+	postgres Postgres
+}
+
+func NewLoyaltyService(pg Postgres) *LoyaltyService {
+	return &LoyaltyService{postgres: pg}
+}
+
+// GetAccount возвращает баланс баллов пользователя и историю последних операций.
+func (s *LoyaltyService) GetAccount(ctx context.Context, userID int64) (models.LoyaltyAccount, error) {
+	return s.postgres.GetLoyaltyAccount(ctx, userID, historyLimit)
+}
+
+// Adjust начисляет (points > 0) или списывает (points < 0) баллы пользователя вручную от имени админа.
+func (s *LoyaltyService) Adjust(ctx context.Context, userID int64, points int, reason string, adminID int64) (models.LoyaltyTransaction, error) {
+	return s.postgres.SaveLoyaltyAdjustment(ctx, models.LoyaltyTransaction{
+		UserID:    userID,
+		Points:    points,
+		Reason:    reason,
+		CreatedBy: &adminID,
+	})
+}
@@ -0,0 +1,56 @@
+package menusrv
+
+import (
+	"context"
+
+	"main_service/internal/models"
+)
+
+type Postgres interface {
+	GetMenu(ctx context.Context) ([]models.MenuCategory, error)
+	SaveCategory(ctx context.Context, category models.MenuCategory) (models.MenuCategory, error)
+	UpdateCategory(ctx context.Context, category models.MenuCategory) error
+	DeactivateCategory(ctx context.Context, id int64) error
+	SaveDish(ctx context.Context, dish models.Dish) (models.Dish, error)
+	UpdateDish(ctx context.Context, dish models.Dish) error
+	DeactivateDish(ctx context.Context, id int64) error
+}
+
+type MenuService struct {
+	postgres Postgres
+}
+
+func NewMenuService(pg Postgres) *MenuService {
+	return &MenuService{postgres: pg}
+}
+
+// GetMenu возвращает меню: разделы с блюдами в порядке показа.
+func (s *MenuService) GetMenu(ctx context.Context) ([]models.MenuCategory, error) {
+	return s.postgres.GetMenu(ctx)
+}
+
+func (s *MenuService) CreateCategory(ctx context.Context, category models.MenuCategory) (models.MenuCategory, error) {
+	return s.postgres.SaveCategory(ctx, category)
+}
+
+func (s *MenuService) UpdateCategory(ctx context.Context, category models.MenuCategory) error {
+	return s.postgres.UpdateCategory(ctx, category)
+}
+
+// DeleteCategory убирает раздел из меню. Сначала из раздела нужно убрать или перенести блюда.
+func (s *MenuService) DeleteCategory(ctx context.Context, id int64) error {
+	return s.postgres.DeactivateCategory(ctx, id)
+}
+
+func (s *MenuService) CreateDish(ctx context.Context, dish models.Dish) (models.Dish, error) {
+	return s.postgres.SaveDish(ctx, dish)
+}
+
+func (s *MenuService) UpdateDish(ctx context.Context, dish models.Dish) error {
+	return s.postgres.UpdateDish(ctx, dish)
+}
+
+// DeleteDish убирает блюдо из меню. Запись о блюде остаётся, чтобы не терять его в старых заказах.
+func (s *MenuService) DeleteDish(ctx context.Context, id int64) error {
+	return s.postgres.DeactivateDish(ctx, id)
+}
@@ -0,0 +1,38 @@
+package promosrv
+
+import (
+	"context"
+
+	"main_service/internal/lib/promo"
+	"main_service/internal/models"
+)
+
+type Postgres interface {
+	SavePromoCode(ctx context.Context, code models.PromoCode) (models.PromoCode, error)
+	GetPromoCodes(ctx context.Context) ([]models.PromoCode, error)
+	DeactivatePromoCode(ctx context.Context, id int64) error
+}
+
+type PromoService struct {
+	postgres Postgres
+}
+
+func NewPromoService(pg Postgres) *PromoService {
+	return &PromoService{postgres: pg}
+}
+
+// CreatePromoCode сохраняет новый промокод. Код хранится в верхнем регистре, чтобы гость мог ввести его как угодно.
+func (s *PromoService) CreatePromoCode(ctx context.Context, code models.PromoCode) (models.PromoCode, error) {
+	code.Code = promo.Normalize(code.Code)
+
+	return s.postgres.SavePromoCode(ctx, code)
+}
+
+func (s *PromoService) GetPromoCodes(ctx context.Context) ([]models.PromoCode, error) {
+	return s.postgres.GetPromoCodes(ctx)
+}
+
+// DeactivatePromoCode выключает промокод: новые брони с ним не принимаются.
+func (s *PromoService) DeactivatePromoCode(ctx context.Context, id int64) error {
+	return s.postgres.DeactivatePromoCode(ctx, id)
+}
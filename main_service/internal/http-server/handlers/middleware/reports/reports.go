@@ -0,0 +1,124 @@
+package reportsrv
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"main_service/internal/config"
+	"main_service/internal/models"
+)
+
+type Postgres interface {
+	GetCovers(ctx context.Context, from, to time.Time, groupBy string) ([]models.CoversStat, error)
+	GetBookedTableHoursPerDay(ctx context.Context, from, to time.Time) ([]models.UtilisationStat, error)
+	GetBookingRates(ctx context.Context, from, to time.Time) (models.BookingRates, error)
+	GetBusiestTables(ctx context.Context, from, to time.Time, limit int) ([]models.TableStat, error)
+	GetRatingsByDay(ctx context.Context, from, to time.Time) ([]models.RatingStat, error)
+	GetRatingsByTable(ctx context.Context, from, to time.Time) ([]models.RatingStat, error)
+}
+
+type ReportService struct {
+	postgres   Postgres
+	restaurant config.Restaurant
+}
+
+func NewReportService(pg Postgres, restaurant config.Restaurant) *ReportService {
+	return &ReportService{
+		postgres:   pg,
+		restaurant: restaurant,
+	}
+}
+
+// Covers возвращает брони и гостей по дням или часам, groupBy - "day" или "hour".
+func (s *ReportService) Covers(ctx context.Context, from, to time.Time, groupBy string) ([]models.CoversStat, error) {
+	return s.postgres.GetCovers(ctx, from, to, groupBy)
+}
+
+// Utilisation возвращает загрузку столов по дням. Каждая бронь занимает свои столы от начала визита
+// до ends_at, доступное время - TablesCount столов на OpeningHours часов в день.
+func (s *ReportService) Utilisation(ctx context.Context, from, to time.Time) ([]models.UtilisationStat, error) {
+	stats, err := s.postgres.GetBookedTableHoursPerDay(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	available := float64(s.restaurant.TablesCount) * s.restaurant.OpeningHours.Hours()
+
+	for i := range stats {
+		stats[i].AvailableHours = available
+		if available > 0 {
+			stats[i].Utilisation = round(stats[i].BookedHours / available * 100)
+		}
+		stats[i].BookedHours = round(stats[i].BookedHours)
+	}
+
+	return stats, nil
+}
+
+// Rates возвращает доли отмен и неявок в процентах и среднее время упреждения брони.
+func (s *ReportService) Rates(ctx context.Context, from, to time.Time) (models.BookingRates, error) {
+	rates, err := s.postgres.GetBookingRates(ctx, from, to)
+	if err != nil {
+		return models.BookingRates{}, err
+	}
+
+	if rates.Total > 0 {
+		rates.CancellationRate = round(float64(rates.Cancelled) / float64(rates.Total) * 100)
+	}
+
+	// Неявки считаются только среди броней, по которым визит уже закрыт.
+	if closed := rates.Completed + rates.NoShow; closed > 0 {
+		rates.NoShowRate = round(float64(rates.NoShow) / float64(closed) * 100)
+	}
+
+	rates.AvgLeadTimeHours = round(rates.AvgLeadTimeHours)
+
+	return rates, nil
+}
+
+// BusiestTables возвращает limit самых загруженных столов.
+func (s *ReportService) BusiestTables(ctx context.Context, from, to time.Time, limit int) ([]models.TableStat, error) {
+	return s.postgres.GetBusiestTables(ctx, from, to, limit)
+}
+
+// Ratings возвращает оценки гостей из отзывов о визитах за период: общую, по дням и по столам.
+func (s *ReportService) Ratings(ctx context.Context, from, to time.Time) (models.RatingsReport, error) {
+	byDay, err := s.postgres.GetRatingsByDay(ctx, from, to)
+	if err != nil {
+		return models.RatingsReport{}, err
+	}
+
+	byTable, err := s.postgres.GetRatingsByTable(ctx, from, to)
+	if err != nil {
+		return models.RatingsReport{}, err
+	}
+
+	// Общая оценка считается по дням, а не по столам: в разбивке по столам отзыв
+	// о комбинации учитывается несколько раз.
+	var overall models.RatingStat
+	var sum float64
+	for i, d := range byDay {
+		overall.Reviews += d.Reviews
+		sum += d.AverageRating * float64(d.Reviews)
+		byDay[i].AverageRating = round(d.AverageRating)
+	}
+	if overall.Reviews > 0 {
+		overall.AverageRating = round(sum / float64(overall.Reviews))
+	}
+
+	for i, t := range byTable {
+		byTable[i].AverageRating = round(t.AverageRating)
+	}
+
+	return models.RatingsReport{
+		Overall: overall,
+		ByDay:   byDay,
+		ByTable: byTable,
+	}, nil
+}
+
+// round округляет значение до двух знаков после запятой.
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
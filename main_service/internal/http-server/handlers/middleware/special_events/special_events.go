@@ -0,0 +1,107 @@
+package specialeventsrv
+
+import (
+	"context"
+	"time"
+
+	"main_service/internal/config"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+)
+
+type Postgres interface {
+	SaveSpecialEvent(ctx context.Context, event models.SpecialEvent) (models.SpecialEvent, error)
+	GetUpcomingSpecialEvents(ctx context.Context, now time.Time) ([]models.SpecialEvent, error)
+	GetSpecialEvent(ctx context.Context, id int64) (models.SpecialEvent, error)
+	CancelSpecialEvent(ctx context.Context, id int64) error
+	ReserveEventSeats(ctx context.Context, reservation models.EventReservation, now time.Time) (models.EventReservation, error)
+	CancelEventReservation(ctx context.Context, eventID, userID int64, now time.Time) error
+	GetEventReservations(ctx context.Context, eventID int64) ([]models.EventReservation, error)
+	HasCustomerTag(ctx context.Context, userID int64, tag string) (bool, error)
+}
+
+type SpecialEventService struct {
+	postgres   Postgres
+	restaurant config.Restaurant
+}
+
+func NewSpecialEventService(pg Postgres, restaurant config.Restaurant) *SpecialEventService {
+	return &SpecialEventService{
+		postgres:   pg,
+		restaurant: restaurant,
+	}
+}
+
+// Attendees - событие и действующие резервации мест на него.
+type Attendees struct {
+	Event        models.SpecialEvent       `json:"event"`
+	Reservations []models.EventReservation `json:"reservations"`
+}
+
+// CreateEvent сохраняет событие. Время события хранится без пояса по часам ресторана,
+// поэтому переданное время сначала переводится в пояс ресторана.
+func (s *SpecialEventService) CreateEvent(ctx context.Context, event models.SpecialEvent) (models.SpecialEvent, error) {
+	event.StartsAt = s.localTime(event.StartsAt)
+	event.EndsAt = s.localTime(event.EndsAt)
+
+	return s.postgres.SaveSpecialEvent(ctx, event)
+}
+
+// GetUpcomingEvents возвращает события, на которые ещё можно купить места, вместе с числом свободных мест.
+func (s *SpecialEventService) GetUpcomingEvents(ctx context.Context) ([]models.SpecialEvent, error) {
+	return s.postgres.GetUpcomingSpecialEvents(ctx, s.localTime(time.Now()))
+}
+
+// CancelEvent отменяет событие, все резервации на него снимаются.
+func (s *SpecialEventService) CancelEvent(ctx context.Context, id int64) error {
+	return s.postgres.CancelSpecialEvent(ctx, id)
+}
+
+// Reserve резервирует seats мест на событии для гостя. Гостям с тегом models.TagBlocked, как и при бронировании
+// стола, резервировать места нельзя - возвращается storage.ErrUserBlocked.
+func (s *SpecialEventService) Reserve(ctx context.Context, eventID, userID int64, seats int) (models.EventReservation, error) {
+	blocked, err := s.postgres.HasCustomerTag(ctx, userID, models.TagBlocked)
+	if err != nil {
+		return models.EventReservation{}, err
+	}
+
+	if blocked {
+		return models.EventReservation{}, storage.ErrUserBlocked
+	}
+
+	return s.postgres.ReserveEventSeats(ctx, models.EventReservation{
+		EventID: eventID,
+		UserID:  userID,
+		Seats:   seats,
+	}, s.localTime(time.Now()))
+}
+
+// CancelReservation отменяет места гостя на событии, пока оно не началось.
+func (s *SpecialEventService) CancelReservation(ctx context.Context, eventID, userID int64) error {
+	return s.postgres.CancelEventReservation(ctx, eventID, userID, s.localTime(time.Now()))
+}
+
+// GetAttendees возвращает список гостей события для админа.
+func (s *SpecialEventService) GetAttendees(ctx context.Context, eventID int64) (Attendees, error) {
+	event, err := s.postgres.GetSpecialEvent(ctx, eventID)
+	if err != nil {
+		return Attendees{}, err
+	}
+
+	reservations, err := s.postgres.GetEventReservations(ctx, eventID)
+	if err != nil {
+		return Attendees{}, err
+	}
+
+	return Attendees{Event: event, Reservations: reservations}, nil
+}
+
+// localTime переводит t в пояс ресторана: в колонки без пояса записываются часы ресторана,
+// и сравнивать с ними можно только время по тем же часам.
+func (s *SpecialEventService) localTime(t time.Time) time.Time {
+	if s.restaurant.Location == nil {
+		return t
+	}
+
+	return t.In(s.restaurant.Location)
+}
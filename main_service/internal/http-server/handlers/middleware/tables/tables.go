@@ -0,0 +1,239 @@
+package tablesrv
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"main_service/internal/config"
+	"main_service/internal/lib/pacing"
+	"main_service/internal/lib/schedule"
+	"main_service/internal/lib/turntime"
+	"main_service/internal/models"
+)
+
+type Postgres interface {
+	SaveCombination(ctx context.Context, combination models.TableCombination) (int, error)
+	GetCombinations(ctx context.Context) ([]models.TableCombination, error)
+	DeactivateCombination(ctx context.Context, id int) error
+	GetTables(ctx context.Context) ([]models.Table, error)
+	SaveTable(ctx context.Context, table models.Table) error
+	GetFloorBookings(ctx context.Context, from, to, seatedFrom time.Time) ([]models.BookingInfo, error)
+	GetBookedTables(ctx context.Context, from, to time.Time) ([]int16, error)
+	SaveBlock(ctx context.Context, block models.TableBlock) (models.TableBlock, error)
+	GetBlocks(ctx context.Context, from time.Time) ([]models.TableBlock, error)
+	DeactivateBlock(ctx context.Context, id int64) error
+	GetBlockedTables(ctx context.Context, from, to time.Time) ([]int16, error)
+	GetBlockConflicts(ctx context.Context, block models.TableBlock) ([]models.BookingInfo, error)
+	SaveZoneSchedule(ctx context.Context, schedule models.ZoneSchedule) (models.ZoneSchedule, error)
+	GetZoneSchedules(ctx context.Context) ([]models.ZoneSchedule, error)
+	DeleteZoneSchedule(ctx context.Context, id int64) error
+}
+
+type Redis interface {
+	GetPacing(ctx context.Context, start time.Time) (int, int, error)
+}
+
+type TableService struct {
+	postgres   Postgres
+	redis      Redis
+	restaurant config.Restaurant
+	pacing     config.Pacing
+}
+
+func NewTableService(pg Postgres, r Redis, restaurant config.Restaurant, pacing config.Pacing) *TableService {
+	return &TableService{
+		postgres:   pg,
+		redis:      r,
+		restaurant: restaurant,
+		pacing:     pacing,
+	}
+}
+
+// CreateCombination сохраняет новую комбинацию столов и возвращает её с присвоенным id.
+func (s *TableService) CreateCombination(ctx context.Context, combination models.TableCombination) (models.TableCombination, error) {
+	id, err := s.postgres.SaveCombination(ctx, combination)
+	if err != nil {
+		return models.TableCombination{}, err
+	}
+	combination.ID = id
+
+	return combination, nil
+}
+
+func (s *TableService) GetCombinations(ctx context.Context) ([]models.TableCombination, error) {
+	return s.postgres.GetCombinations(ctx)
+}
+
+func (s *TableService) DeleteCombination(ctx context.Context, id int) error {
+	return s.postgres.DeactivateCombination(ctx, id)
+}
+
+// CreateBlock блокирует столы на период. Если withConflicts, дополнительно возвращает уже сделанные брони,
+// которые пересекаются с блокировкой: их блокировка не отменяет, с гостями нужно договориться отдельно.
+func (s *TableService) CreateBlock(
+	ctx context.Context,
+	block models.TableBlock,
+	withConflicts bool,
+) (models.TableBlock, []models.BookingInfo, error) {
+	block, err := s.postgres.SaveBlock(ctx, block)
+	if err != nil {
+		return models.TableBlock{}, nil, err
+	}
+
+	if !withConflicts {
+		return block, nil, nil
+	}
+
+	conflicts, err := s.postgres.GetBlockConflicts(ctx, block)
+	if err != nil {
+		return block, nil, err
+	}
+
+	return block, conflicts, nil
+}
+
+// GetBlocks возвращает текущие и будущие блокировки столов. Время блокировок хранится по часам ресторана.
+func (s *TableService) GetBlocks(ctx context.Context) ([]models.TableBlock, error) {
+	return s.postgres.GetBlocks(ctx, s.restaurant.Local(time.Now()))
+}
+
+func (s *TableService) DeleteBlock(ctx context.Context, id int64) error {
+	return s.postgres.DeactivateBlock(ctx, id)
+}
+
+// CreateZoneSchedule добавляет залу период, когда он открыт.
+func (s *TableService) CreateZoneSchedule(ctx context.Context, schedule models.ZoneSchedule) (models.ZoneSchedule, error) {
+	return s.postgres.SaveZoneSchedule(ctx, schedule)
+}
+
+func (s *TableService) GetZoneSchedules(ctx context.Context) ([]models.ZoneSchedule, error) {
+	return s.postgres.GetZoneSchedules(ctx)
+}
+
+func (s *TableService) DeleteZoneSchedule(ctx context.Context, id int64) error {
+	return s.postgres.DeleteZoneSchedule(ctx, id)
+}
+
+// Availability возвращает состояние каждого стола для брони компании partySize на время at:
+// заблокирован, если блокировка пересекается с визитом, занят, если визит пересекается с другой бронью
+// с учётом времени на подготовку стола. Длительность визита берётся по правилам длительности.
+// Свободные столы помечаются pacing_limit, если кухня уже не примет компанию в этот интервал.
+// Столы залов, закрытых по расписанию на время визита, в ответ не попадают.
+func (s *TableService) Availability(ctx context.Context, at time.Time, partySize int16) ([]models.TableAvailability, error) {
+	until := at.Add(turntime.Duration(s.restaurant, partySize, at))
+
+	tables, err := s.postgres.GetTables(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	schedules, err := s.postgres.GetZoneSchedules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	blocked, err := s.postgres.GetBlockedTables(ctx, at, until)
+	if err != nil {
+		return nil, err
+	}
+
+	booked, err := s.postgres.GetBookedTables(ctx, at, until)
+	if err != nil {
+		return nil, err
+	}
+
+	covers, bookings, err := s.redis.GetPacing(ctx, pacing.Slot(at, s.pacing.Interval))
+	if err != nil {
+		return nil, err
+	}
+	paced := !pacing.Fits(pacing.Limit(s.pacing, s.restaurant.DayParts, at), covers, bookings, int(partySize))
+
+	availability := make([]models.TableAvailability, 0, len(tables))
+	for _, t := range tables {
+		if !schedule.IsOpen(schedules, t.Zone, at, until) {
+			continue
+		}
+
+		status := models.AvailabilityFree
+		switch {
+		case slices.Contains(blocked, t.ID):
+			status = models.AvailabilityBlocked
+		case slices.Contains(booked, t.ID):
+			status = models.AvailabilityBooked
+		case paced:
+			status = models.AvailabilityPacingLimit
+		}
+
+		availability = append(availability, models.TableAvailability{
+			TableID: t.ID,
+			Zone:    t.Zone,
+			Status:  status,
+		})
+	}
+
+	return availability, nil
+}
+
+// SaveTable добавляет стол или меняет его зал и место на плане зала.
+func (s *TableService) SaveTable(ctx context.Context, table models.Table) error {
+	return s.postgres.SaveTable(ctx, table)
+}
+
+// FloorPlan возвращает план зала с состоянием каждого стола на момент now. Приоритет состояний:
+// гости уже сидят (seated), стол заблокирован (blocked), скоро придут гости по брони (booked_soon), свободен (free).
+func (s *TableService) FloorPlan(ctx context.Context, now time.Time) ([]models.FloorPlanTable, error) {
+	tables, err := s.postgres.GetTables(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Брони и блокировки хранятся по часам ресторана, поэтому и now сравнивается по ним.
+	now = s.restaurant.Local(now)
+	soon := now.Add(s.restaurant.BookedSoonWindow)
+
+	blocked, err := s.postgres.GetBlockedTables(ctx, now, soon)
+	if err != nil {
+		return nil, err
+	}
+
+	// Опоздавшие гости ещё могут прийти, пока не прошло время брони. Засидевшиеся гости
+	// считаются за столом ещё на время подготовки стола после конца брони.
+	bookings, err := s.postgres.GetFloorBookings(ctx, now, soon, now.Add(-s.restaurant.CleaningBuffer))
+	if err != nil {
+		return nil, err
+	}
+
+	plan := make([]models.FloorPlanTable, 0, len(tables))
+	for _, t := range tables {
+		seated := findBooking(bookings, t.ID, true)
+		upcoming := findBooking(bookings, t.ID, false)
+
+		ft := models.FloorPlanTable{Table: t, Status: models.FloorStatusFree}
+		switch {
+		case seated != nil:
+			ft.Status = models.FloorStatusSeated
+			ft.Booking = seated
+		case slices.Contains(blocked, t.ID):
+			ft.Status = models.FloorStatusBlocked
+		case upcoming != nil:
+			ft.Status = models.FloorStatusBookedSoon
+			ft.Booking = upcoming
+		}
+
+		plan = append(plan, ft)
+	}
+
+	return plan, nil
+}
+
+// findBooking возвращает первую по времени бронь стола tableID, гости которой уже сидят (checkedIn) или ещё не пришли.
+func findBooking(bookings []models.BookingInfo, tableID int16, checkedIn bool) *models.BookingInfo {
+	for i := range bookings {
+		if (bookings[i].CheckedInAt != nil) == checkedIn && slices.Contains(bookings[i].TableIDs, tableID) {
+			return &bookings[i]
+		}
+	}
+
+	return nil
+}
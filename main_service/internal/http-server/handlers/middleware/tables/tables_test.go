@@ -0,0 +1,115 @@
+package tablesrv
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"main_service/internal/config"
+	"main_service/internal/models"
+)
+
+// fakePostgres хранит брони и блокировки так же, как Postgres: время без пояса, по часам ресторана.
+// Как и pgx, при сравнении он отбрасывает пояс у переданного времени.
+type fakePostgres struct {
+	Postgres
+
+	tables   []models.Table
+	bookings []models.BookingInfo
+	blocks   []models.TableBlock
+}
+
+// wallClock повторяет то, что pgx делает со временем для колонки TIMESTAMP: оставляет часы и минуты, пояс - UTC.
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
+
+func (f *fakePostgres) GetTables(ctx context.Context) ([]models.Table, error) {
+	return f.tables, nil
+}
+
+func (f *fakePostgres) GetBlockedTables(ctx context.Context, from, to time.Time) ([]int16, error) {
+	from, to = wallClock(from), wallClock(to)
+
+	var tableIDs []int16
+	for _, b := range f.blocks {
+		if b.StartsAt.Before(to) && b.EndsAt.After(from) {
+			tableIDs = append(tableIDs, b.TableIDs...)
+		}
+	}
+
+	return tableIDs, nil
+}
+
+func (f *fakePostgres) GetFloorBookings(ctx context.Context, from, to, seatedFrom time.Time) ([]models.BookingInfo, error) {
+	from, to, seatedFrom = wallClock(from), wallClock(to), wallClock(seatedFrom)
+
+	var bookings []models.BookingInfo
+	for _, b := range f.bookings {
+		if b.BookingTime.Before(to) && (b.EndsAt.After(from) || b.CheckedInAt != nil && b.EndsAt.After(seatedFrom)) {
+			bookings = append(bookings, b)
+		}
+	}
+
+	return bookings, nil
+}
+
+func TestFloorPlanUsesRestaurantClock(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	if err != nil {
+		t.Fatalf("time.LoadLocation() error = %v", err)
+	}
+
+	checkedIn := time.Date(2025, 9, 20, 16, 5, 0, 0, time.UTC)
+	pg := &fakePostgres{
+		tables: []models.Table{{ID: 1}, {ID: 2}, {ID: 3}},
+		bookings: []models.BookingInfo{
+			// Гости сели в 19:00 по Москве.
+			{
+				ID:          1,
+				TableIDs:    []int16{1},
+				BookingTime: time.Date(2025, 9, 20, 19, 0, 0, 0, time.UTC),
+				EndsAt:      time.Date(2025, 9, 20, 21, 0, 0, 0, time.UTC),
+				CheckedInAt: &checkedIn,
+			},
+			// Гости придут в 20:00 по Москве.
+			{
+				ID:          2,
+				TableIDs:    []int16{2},
+				BookingTime: time.Date(2025, 9, 20, 20, 0, 0, 0, time.UTC),
+				EndsAt:      time.Date(2025, 9, 20, 22, 0, 0, 0, time.UTC),
+			},
+		},
+		blocks: []models.TableBlock{
+			{
+				TableIDs: []int16{3},
+				StartsAt: time.Date(2025, 9, 20, 19, 0, 0, 0, time.UTC),
+				EndsAt:   time.Date(2025, 9, 20, 23, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	s := NewTableService(pg, nil, config.Restaurant{
+		Location:         moscow,
+		BookedSoonWindow: time.Hour,
+		CleaningBuffer:   15 * time.Minute,
+	}, config.Pacing{})
+
+	// 16:30 UTC - 19:30 по Москве.
+	plan, err := s.FloorPlan(context.Background(), time.Date(2025, 9, 20, 16, 30, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("FloorPlan() error = %v", err)
+	}
+
+	want := map[int16]string{
+		1: models.FloorStatusSeated,
+		2: models.FloorStatusBookedSoon,
+		3: models.FloorStatusBlocked,
+	}
+
+	for _, table := range plan {
+		if table.Status != want[table.ID] {
+			t.Errorf("table %d status = %s, want %s", table.ID, table.Status, want[table.ID])
+		}
+	}
+}
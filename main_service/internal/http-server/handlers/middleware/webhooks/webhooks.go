@@ -0,0 +1,215 @@
+package webhooksrv
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"main_service/internal/config"
+	"main_service/internal/lib/logger/sl"
+	"main_service/internal/lib/webhook"
+	"main_service/internal/models"
+)
+
+const (
+	// batchSize - сколько доставок worker отправляет за один проход.
+	batchSize = 50
+	// attemptsHistoryLimit - сколько последних попыток доставки показывается админу.
+	attemptsHistoryLimit = 100
+)
+
+type Postgres interface {
+	SaveWebhook(ctx context.Context, webhook models.Webhook) (int64, error)
+	GetWebhooks(ctx context.Context) ([]models.Webhook, error)
+	DeleteWebhook(ctx context.Context, id int64) error
+	EnableWebhook(ctx context.Context, id int64) error
+	GetWebhookAttempts(ctx context.Context, webhookID int64, limit int) ([]models.WebhookAttempt, error)
+	ClaimWebhookDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.WebhookDelivery, error)
+	SaveWebhookAttempt(ctx context.Context, attempt models.WebhookAttempt) error
+	CompleteWebhookDelivery(ctx context.Context, deliveryID, webhookID int64) error
+	RescheduleWebhookDelivery(ctx context.Context, deliveryID int64, at time.Time) error
+	FailWebhookDelivery(ctx context.Context, deliveryID, webhookID int64, disableAfter int) (bool, error)
+}
+
+type Sender interface {
+	Send(ctx context.Context, req webhook.Request) (int, error)
+}
+
+// Payload - тело запроса, которое получает подписчик.
+type Payload struct {
+	ID        int64               `json:"id"`
+	Type      string              `json:"type"`
+	CreatedAt time.Time           `json:"created_at"`
+	Data      models.BookingEvent `json:"data"`
+}
+
+type WebhookService struct {
+	log      *slog.Logger
+	postgres Postgres
+	sender   Sender
+	cfg      config.Webhooks
+}
+
+func NewWebhookService(log *slog.Logger, pg Postgres, sender Sender, cfg config.Webhooks) *WebhookService {
+	return &WebhookService{
+		log:      log,
+		postgres: pg,
+		sender:   sender,
+		cfg:      cfg,
+	}
+}
+
+// CreateWebhook сохраняет подписку. Если секрет не задан, он генерируется.
+// Возвращённая подписка содержит секрет - позже его получить нельзя.
+func (s *WebhookService) CreateWebhook(ctx context.Context, wh models.Webhook) (models.Webhook, error) {
+	if wh.Secret == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return models.Webhook{}, err
+		}
+		wh.Secret = hex.EncodeToString(secret)
+	}
+
+	id, err := s.postgres.SaveWebhook(ctx, wh)
+	if err != nil {
+		return models.Webhook{}, err
+	}
+	wh.ID = id
+	wh.IsActive = true
+	wh.CreatedAt = time.Now()
+
+	return wh, nil
+}
+
+func (s *WebhookService) GetWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	return s.postgres.GetWebhooks(ctx)
+}
+
+func (s *WebhookService) DeleteWebhook(ctx context.Context, id int64) error {
+	return s.postgres.DeleteWebhook(ctx, id)
+}
+
+// EnableWebhook включает подписку, отключённую из-за неудачных доставок. Накопившиеся за это время
+// события будут доставлены.
+func (s *WebhookService) EnableWebhook(ctx context.Context, id int64) error {
+	return s.postgres.EnableWebhook(ctx, id)
+}
+
+// GetAttempts возвращает последние попытки доставки по подписке.
+func (s *WebhookService) GetAttempts(ctx context.Context, webhookID int64) ([]models.WebhookAttempt, error) {
+	return s.postgres.GetWebhookAttempts(ctx, webhookID, attemptsHistoryLimit)
+}
+
+// Run доставляет события броней подписчикам, пока не отменён ctx. Доставки создаются
+// вместе с событиями в storage.
+func (s *WebhookService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		s.ProcessOnce(ctx, time.Now())
+	}
+}
+
+// ProcessOnce выполняет один проход worker: отправляет доставки, время которых наступило к now.
+func (s *WebhookService) ProcessOnce(ctx context.Context, now time.Time) {
+	const op = "webhooksrv.ProcessOnce"
+
+	log := s.log.With(slog.String("op", op))
+
+	// Каждая доставка забирается прямо перед отправкой и откладывается на время одного запроса с запасом:
+	// если сервис упадёт, её заберёт другой экземпляр, а пока идут остальные запросы прохода, аренда не истечёт.
+	// Доставки подписки, отключённой в этом проходе, больше не забираются и ждут её включения.
+	started := time.Now()
+	for range batchSize {
+		leaseUntil := now.Add(time.Since(started) + 2*s.cfg.Timeout)
+
+		deliveries, err := s.postgres.ClaimWebhookDeliveries(ctx, now, leaseUntil, 1)
+		if err != nil {
+			log.Error("failed to claim webhook delivery", sl.Err(err))
+
+			return
+		}
+
+		if len(deliveries) == 0 {
+			return
+		}
+
+		d := deliveries[0]
+		if err := s.deliver(ctx, d, now); err != nil {
+			log.Error("failed to process webhook delivery", sl.Err(err), slog.Int64("deliveryID", d.ID))
+		}
+	}
+}
+
+// deliver делает одну попытку доставки, записывает её и решает, что делать дальше:
+// завершить доставку, повторить её позже или признать неудачной.
+func (s *WebhookService) deliver(ctx context.Context, d models.WebhookDelivery, now time.Time) error {
+	body, err := json.Marshal(Payload{
+		ID:        d.Event.ID,
+		Type:      d.Event.EventType,
+		CreatedAt: d.Event.CreatedAt,
+		Data:      d.Event,
+	})
+	if err != nil {
+		return err
+	}
+
+	attempt := d.Attempts + 1
+
+	started := time.Now()
+	statusCode, sendErr := s.sender.Send(ctx, webhook.Request{
+		URL:        d.URL,
+		Secret:     d.Secret,
+		DeliveryID: d.ID,
+		Event:      d.Event.EventType,
+		Body:       body,
+	})
+
+	record := models.WebhookAttempt{
+		DeliveryID: d.ID,
+		Attempt:    attempt,
+		StatusCode: statusCode,
+		DurationMs: int(time.Since(started).Milliseconds()),
+	}
+	if sendErr != nil {
+		record.Error = sendErr.Error()
+	}
+
+	if err := s.postgres.SaveWebhookAttempt(ctx, record); err != nil {
+		return err
+	}
+
+	if sendErr == nil {
+		return s.postgres.CompleteWebhookDelivery(ctx, d.ID, d.WebhookID)
+	}
+
+	if attempt < s.cfg.MaxAttempts {
+		next := now.Add(webhook.Backoff(attempt, s.cfg.BaseBackoff, s.cfg.MaxBackoff))
+
+		return s.postgres.RescheduleWebhookDelivery(ctx, d.ID, next)
+	}
+
+	disabled, err := s.postgres.FailWebhookDelivery(ctx, d.ID, d.WebhookID, s.cfg.DisableAfter)
+	if err != nil {
+		return err
+	}
+
+	if disabled {
+		s.log.Warn("webhook disabled after repeated failures",
+			slog.Int64("webhookID", d.WebhookID),
+			slog.String("url", d.URL),
+		)
+	}
+
+	return nil
+}
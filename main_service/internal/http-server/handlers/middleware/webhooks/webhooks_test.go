@@ -0,0 +1,201 @@
+package webhooksrv
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"main_service/internal/config"
+	"main_service/internal/lib/webhook"
+	"main_service/internal/models"
+)
+
+// fakePostgres хранит одну подписку и её доставки в памяти.
+type fakePostgres struct {
+	Postgres
+
+	webhook    models.Webhook
+	deliveries map[int64]*fakeDelivery
+	attempts   []models.WebhookAttempt
+}
+
+type fakeDelivery struct {
+	models.WebhookDelivery
+	status string
+	nextAt time.Time
+}
+
+func newFakePostgres(url, secret string, events ...models.BookingEvent) *fakePostgres {
+	pg := &fakePostgres{
+		webhook:    models.Webhook{ID: 1, URL: url, Secret: secret, IsActive: true},
+		deliveries: make(map[int64]*fakeDelivery),
+	}
+
+	for i, e := range events {
+		id := int64(i + 1)
+		pg.deliveries[id] = &fakeDelivery{
+			WebhookDelivery: models.WebhookDelivery{ID: id, WebhookID: 1, URL: url, Secret: secret, Event: e},
+			status:          models.DeliveryPending,
+		}
+	}
+
+	return pg
+}
+
+func (f *fakePostgres) ClaimWebhookDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.WebhookDelivery, error) {
+	var claimed []models.WebhookDelivery
+	for id := int64(1); id <= int64(len(f.deliveries)); id++ {
+		d := f.deliveries[id]
+		if !f.webhook.IsActive || d.status != models.DeliveryPending || d.nextAt.After(now) {
+			continue
+		}
+		if len(claimed) == limit {
+			break
+		}
+		d.nextAt = leaseUntil
+		claimed = append(claimed, d.WebhookDelivery)
+	}
+
+	return claimed, nil
+}
+
+func (f *fakePostgres) SaveWebhookAttempt(ctx context.Context, attempt models.WebhookAttempt) error {
+	f.attempts = append(f.attempts, attempt)
+	f.deliveries[attempt.DeliveryID].Attempts = attempt.Attempt
+
+	return nil
+}
+
+func (f *fakePostgres) CompleteWebhookDelivery(ctx context.Context, deliveryID, webhookID int64) error {
+	f.deliveries[deliveryID].status = models.DeliveryDelivered
+	f.webhook.FailureCount = 0
+
+	return nil
+}
+
+func (f *fakePostgres) RescheduleWebhookDelivery(ctx context.Context, deliveryID int64, at time.Time) error {
+	f.deliveries[deliveryID].nextAt = at
+
+	return nil
+}
+
+func (f *fakePostgres) FailWebhookDelivery(ctx context.Context, deliveryID, webhookID int64, disableAfter int) (bool, error) {
+	f.deliveries[deliveryID].status = models.DeliveryFailed
+	f.webhook.FailureCount++
+	if f.webhook.FailureCount >= disableAfter {
+		f.webhook.IsActive = false
+	}
+
+	return !f.webhook.IsActive, nil
+}
+
+func testConfig() config.Webhooks {
+	return config.Webhooks{
+		Timeout:      time.Second,
+		MaxAttempts:  3,
+		BaseBackoff:  time.Minute,
+		MaxBackoff:   time.Hour,
+		DisableAfter: 2,
+	}
+}
+
+func TestDeliveryRetriesUntilReceiverAccepts(t *testing.T) {
+	const secret = "secret"
+
+	var calls atomic.Int32
+	var payload Payload
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get(webhook.HeaderTimestamp), 10, 64)
+		if !webhook.Verify(secret, timestamp, body, r.Header.Get(webhook.HeaderSignature)) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		_ = json.Unmarshal(body, &payload)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	pg := newFakePostgres(receiver.URL, secret, models.BookingEvent{ID: 10, BookingID: 5, EventType: models.EventBookingCreated})
+	s := NewWebhookService(slog.New(slog.NewTextHandler(io.Discard, nil)), pg, webhook.NewLocalClient(time.Second), testConfig())
+
+	now := time.Now()
+	s.ProcessOnce(context.Background(), now)
+
+	d := pg.deliveries[1]
+	if d.status != models.DeliveryPending || d.Attempts != 1 {
+		t.Fatalf("after failed attempt: status = %s, attempts = %d", d.status, d.Attempts)
+	}
+
+	if want := now.Add(time.Minute); !d.nextAt.Equal(want) {
+		t.Errorf("next attempt at %v, want %v", d.nextAt, want)
+	}
+
+	// До наступления времени повтора доставка не отправляется.
+	s.ProcessOnce(context.Background(), now.Add(30*time.Second))
+	if calls.Load() != 1 {
+		t.Fatalf("receiver called %d times before backoff elapsed, want 1", calls.Load())
+	}
+
+	s.ProcessOnce(context.Background(), now.Add(time.Minute))
+
+	if d.status != models.DeliveryDelivered {
+		t.Fatalf("status = %s, want %s", d.status, models.DeliveryDelivered)
+	}
+
+	if len(pg.attempts) != 2 || pg.attempts[0].StatusCode != http.StatusInternalServerError || pg.attempts[1].StatusCode != http.StatusOK {
+		t.Errorf("unexpected attempts: %+v", pg.attempts)
+	}
+
+	if payload.ID != 10 || payload.Type != models.EventBookingCreated || payload.Data.BookingID != 5 {
+		t.Errorf("unexpected payload: %+v", payload)
+	}
+}
+
+func TestWebhookDisabledAfterRepeatedFailures(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer receiver.Close()
+
+	pg := newFakePostgres(receiver.URL, "secret",
+		models.BookingEvent{ID: 1, EventType: models.EventBookingCreated},
+		models.BookingEvent{ID: 2, EventType: models.EventBookingCancelled},
+		models.BookingEvent{ID: 3, EventType: models.EventBookingCreated},
+	)
+	cfg := testConfig()
+	s := NewWebhookService(slog.New(slog.NewTextHandler(io.Discard, nil)), pg, webhook.NewLocalClient(time.Second), cfg)
+
+	now := time.Now()
+	for i := 0; i < cfg.MaxAttempts; i++ {
+		s.ProcessOnce(context.Background(), now)
+		now = now.Add(cfg.MaxBackoff)
+	}
+
+	if pg.webhook.IsActive {
+		t.Fatal("webhook is still active after repeated failures")
+	}
+
+	if pg.deliveries[1].status != models.DeliveryFailed || pg.deliveries[2].status != models.DeliveryFailed {
+		t.Errorf("first deliveries should fail: %s, %s", pg.deliveries[1].status, pg.deliveries[2].status)
+	}
+
+	// Доставка третьего события отложена до повторного включения подписки.
+	if pg.deliveries[3].status != models.DeliveryPending {
+		t.Errorf("third delivery status = %s, want %s", pg.deliveries[3].status, models.DeliveryPending)
+	}
+}
@@ -0,0 +1,70 @@
+package payments
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	bookingsrv "main_service/internal/http-server/handlers/middleware/booking"
+	resp "main_service/internal/lib/api/response"
+	"main_service/internal/lib/logger/sl"
+	"main_service/internal/lib/payment"
+	"main_service/internal/storage"
+	"net/http"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+)
+
+// maxCallbackSize - максимальный размер тела уведомления платёжного провайдера.
+const maxCallbackSize = 64 << 10
+
+// Callback принимает уведомление платёжного провайдера об оплате депозита. Запрос приходит без токена
+// пользователя, поэтому подлинность проверяется по подписи. Ошибки возвращаются HTTP-статусом,
+// чтобы провайдер повторил уведомление, если его не удалось обработать.
+func Callback(log *slog.Logger, bookingService *bookingsrv.BookingService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.payments.Callback"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxCallbackSize))
+		if err != nil {
+			log.Error("failed to read callback body", sl.Err(err))
+
+			http.Error(w, "bad request", http.StatusBadRequest)
+
+			return
+		}
+
+		if err := bookingService.HandlePaymentCallback(r.Context(), body, r.Header); err != nil {
+			if errors.Is(err, payment.ErrInvalidSignature) {
+				log.Warn("payment callback with invalid signature")
+
+				http.Error(w, "invalid signature", http.StatusUnauthorized)
+
+				return
+			}
+
+			if errors.Is(err, storage.ErrPaymentNotFound) {
+				log.Warn("payment callback for unknown payment")
+
+				http.Error(w, "not found", http.StatusNotFound)
+
+				return
+			}
+
+			log.Error("failed to handle payment callback", sl.Err(err))
+
+			http.Error(w, "internal error", http.StatusInternalServerError)
+
+			return
+		}
+
+		log.Info("payment callback handled")
+
+		render.JSON(w, r, resp.OK())
+	}
+}
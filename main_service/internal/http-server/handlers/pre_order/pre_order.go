@@ -0,0 +1,157 @@
+package preorder
+
+import (
+	"errors"
+	"log/slog"
+	"main_service/internal/clients/sso/grpc"
+	booktable "main_service/internal/http-server/handlers/book_table"
+	bookingsrv "main_service/internal/http-server/handlers/middleware/booking"
+	"main_service/internal/lib/api/auth"
+	resp "main_service/internal/lib/api/response"
+	"main_service/internal/lib/logger/sl"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator"
+)
+
+// Request - новый предзаказ брони целиком. Пустой список отменяет предзаказ.
+type Request struct {
+	Items []booktable.PreOrderItem `json:"items" validate:"max=30,dive"`
+}
+
+// Get возвращает предзаказ брони. Гость видит только свои брони, админ - любые.
+func Get(log *slog.Logger, authClient *grpc.Client, bookingService *bookingsrv.BookingService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.pre-order.Get"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		actor, bookingID, ok := parseRequest(log, authClient, w, r)
+		if !ok {
+			return
+		}
+
+		items, err := bookingService.GetPreOrder(r.Context(), bookingID, actor)
+		if err != nil {
+			if errors.Is(err, storage.ErrBookingNotFound) {
+				render.JSON(w, r, resp.Error("Booking not found"))
+
+				return
+			}
+
+			log.Error("failed to get pre-order", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to fetch pre-order"))
+
+			return
+		}
+
+		render.JSON(w, r, resp.OKWithData(items))
+	}
+}
+
+// Update заменяет предзаказ брони. Гость может менять предзаказ своей брони до отсечки перед визитом,
+// админ - любой брони в любое время. Кухня получает уведомление с новым предзаказом.
+func Update(log *slog.Logger, authClient *grpc.Client, bookingService *bookingsrv.BookingService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.pre-order.Update"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		actor, bookingID, ok := parseRequest(log, authClient, w, r)
+		if !ok {
+			return
+		}
+
+		var req Request
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to decode request"))
+
+			return
+		}
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			log.Error("invalid request", sl.Err(err))
+
+			render.JSON(w, r, resp.ValidationError(validateErr))
+
+			return
+		}
+
+		items, err := bookingService.UpdatePreOrder(r.Context(), bookingID, booktable.PreOrderItems(req.Items), actor)
+		if err != nil {
+			if errors.Is(err, storage.ErrBookingNotFound) {
+				render.JSON(w, r, resp.Error("Booking not found"))
+
+				return
+			} else if errors.Is(err, storage.ErrUnexpectedStatus) {
+				render.JSON(w, r, resp.Error("Pre-order can only be changed for active bookings"))
+
+				return
+			} else if errors.Is(err, storage.ErrPreOrderClosed) {
+				log.Warn("pre-order cutoff passed", slog.Int64("bookingID", bookingID))
+
+				render.JSON(w, r, resp.Error("It is too late to change the pre-order"))
+
+				return
+			} else if errors.Is(err, storage.ErrDishNotFound) {
+				render.JSON(w, r, resp.Error("Pre-ordered dish not found in the menu"))
+
+				return
+			} else if errors.Is(err, storage.ErrDishUnavailable) {
+				render.JSON(w, r, resp.Error("Pre-ordered dish is not available"))
+
+				return
+			} else if errors.Is(err, storage.ErrPreOrderTooLarge) {
+				render.JSON(w, r, resp.Error("Too many dishes pre-ordered for this party size"))
+
+				return
+			}
+
+			log.Error("failed to update pre-order", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to update pre-order"))
+
+			return
+		}
+
+		log.Info("pre-order updated", slog.Int64("bookingID", bookingID), slog.Int("items", len(items)))
+
+		render.JSON(w, r, resp.OKWithData(items))
+	}
+}
+
+// parseRequest определяет, кто делает запрос, и читает id брони из пути. При ошибке ответ уже записан.
+func parseRequest(log *slog.Logger, authClient *grpc.Client, w http.ResponseWriter, r *http.Request) (models.Actor, int64, bool) {
+	actor, ok := auth.Actor(log, authClient, w, r)
+	if !ok {
+		return models.Actor{}, 0, false
+	}
+
+	bookingID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil || bookingID <= 0 {
+		log.Warn("invalid booking id", slog.String("id", chi.URLParam(r, "id")))
+
+		render.JSON(w, r, resp.Error("Invalid booking id"))
+
+		return models.Actor{}, 0, false
+	}
+
+	return actor, bookingID, true
+}
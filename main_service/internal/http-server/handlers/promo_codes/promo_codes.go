@@ -0,0 +1,186 @@
+package promocodes
+
+import (
+	"errors"
+	"log/slog"
+	"main_service/internal/clients/sso/grpc"
+	promosrv "main_service/internal/http-server/handlers/middleware/promo"
+	"main_service/internal/lib/api/auth"
+	resp "main_service/internal/lib/api/response"
+	"main_service/internal/lib/logger/sl"
+	"main_service/internal/lib/promo"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator"
+)
+
+// codePattern - допустимый вид промокода после приведения к верхнему регистру.
+var codePattern = regexp.MustCompile(`^[A-Z0-9_-]+$`)
+
+// CreateRequest - новый промокод. Код действует для визитов в [ValidFrom, ValidUntil),
+// Weekdays ограничивает дни недели визита, MaxUses - число использований (не задан - без ограничения).
+// Подарочный сертификат - код с MaxUses = 1.
+type CreateRequest struct {
+	Code        string    `json:"code" validate:"required,min=3,max=32"`
+	Description string    `json:"description" validate:"required,max=200"`
+	ValidFrom   time.Time `json:"validFrom" validate:"required"`
+	ValidUntil  time.Time `json:"validUntil" validate:"required"`
+	MaxUses     *int      `json:"maxUses" validate:"omitempty,gt=0"`
+	Weekdays    []string  `json:"weekdays" validate:"omitempty,max=7,unique,dive,oneof=monday tuesday wednesday thursday friday saturday sunday"`
+}
+
+// Create добавляет промокод. Доступно только админам.
+func Create(log *slog.Logger, authClient *grpc.Client, promoService *promosrv.PromoService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.promo-codes.Create"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		adminID, ok := auth.RequireAdmin(log, authClient, w, r)
+		if !ok {
+			return
+		}
+
+		var req CreateRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to decode request"))
+
+			return
+		}
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			log.Error("invalid request", sl.Err(err))
+
+			render.JSON(w, r, resp.ValidationError(validateErr))
+
+			return
+		}
+
+		if !codePattern.MatchString(promo.Normalize(req.Code)) {
+			render.JSON(w, r, resp.Error("Field Code may contain only letters, digits, '-' and '_'"))
+
+			return
+		}
+
+		if !req.ValidUntil.After(req.ValidFrom) {
+			render.JSON(w, r, resp.Error("Field ValidUntil must be after ValidFrom"))
+
+			return
+		}
+
+		code, err := promoService.CreatePromoCode(r.Context(), models.PromoCode{
+			Code:        req.Code,
+			Description: strings.TrimSpace(req.Description),
+			ValidFrom:   req.ValidFrom,
+			ValidUntil:  req.ValidUntil,
+			MaxUses:     req.MaxUses,
+			Weekdays:    req.Weekdays,
+			CreatedBy:   adminID,
+		})
+		if err != nil {
+			if errors.Is(err, storage.ErrPromoCodeExists) {
+				log.Warn("failed to create promo code, code already exists", slog.String("code", req.Code))
+
+				render.JSON(w, r, resp.Error("Promo code already exists"))
+
+				return
+			}
+
+			log.Error("failed to create promo code", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to create promo code"))
+
+			return
+		}
+
+		log.Info("promo code created", slog.Int64("promoCodeID", code.ID), slog.String("code", code.Code))
+
+		render.JSON(w, r, resp.OKWithData(code))
+	}
+}
+
+// List возвращает все промокоды вместе с числом использований. Доступно только админам.
+func List(log *slog.Logger, authClient *grpc.Client, promoService *promosrv.PromoService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.promo-codes.List"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		if _, ok := auth.RequireAdmin(log, authClient, w, r); !ok {
+			return
+		}
+
+		codes, err := promoService.GetPromoCodes(r.Context())
+		if err != nil {
+			log.Error("failed to get promo codes", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to fetch promo codes"))
+
+			return
+		}
+
+		render.JSON(w, r, resp.OKWithData(codes))
+	}
+}
+
+// Delete выключает промокод. Доступно только админам.
+func Delete(log *slog.Logger, authClient *grpc.Client, promoService *promosrv.PromoService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.promo-codes.Delete"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		if _, ok := auth.RequireAdmin(log, authClient, w, r); !ok {
+			return
+		}
+
+		id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil || id <= 0 {
+			log.Warn("invalid promo code id", slog.String("id", chi.URLParam(r, "id")))
+
+			render.JSON(w, r, resp.Error("Invalid promo code id"))
+
+			return
+		}
+
+		if err := promoService.DeactivatePromoCode(r.Context(), id); err != nil {
+			if errors.Is(err, storage.ErrPromoCodeNotFound) {
+				render.JSON(w, r, resp.Error("Promo code not found"))
+
+				return
+			}
+
+			log.Error("failed to deactivate promo code", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to deactivate promo code"))
+
+			return
+		}
+
+		log.Info("promo code deactivated", slog.Int64("promoCodeID", id))
+
+		render.JSON(w, r, resp.OK())
+	}
+}
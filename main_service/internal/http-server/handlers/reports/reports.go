@@ -0,0 +1,248 @@
+package reports
+
+import (
+	"log/slog"
+	"main_service/internal/clients/sso/grpc"
+	reportsrv "main_service/internal/http-server/handlers/middleware/reports"
+	"main_service/internal/lib/api/auth"
+	resp "main_service/internal/lib/api/response"
+	"main_service/internal/lib/logger/sl"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator"
+)
+
+const (
+	dateLayout = "2006-01-02"
+
+	// maxRangeDays ограничивает период отчёта, чтобы агрегаты не сканировали всю таблицу.
+	maxRangeDays = 366
+
+	defaultTablesLimit = 10
+)
+
+type RangeRequest struct {
+	From  string `validate:"required"`
+	To    string `validate:"required"`
+	Group string `validate:"omitempty,oneof=day hour"`
+	Limit int    `validate:"omitempty,gt=0,lte=100"`
+}
+
+// Covers возвращает количество броней и гостей по дням или часам: GET /reports/covers?from=&to=&group=day|hour
+func Covers(log *slog.Logger, authClient *grpc.Client, reportService *reportsrv.ReportService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.reports.Covers"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		req, from, to, ok := parseRequest(log, authClient, w, r)
+		if !ok {
+			return
+		}
+
+		if req.Group == "" {
+			req.Group = "day"
+		}
+
+		stats, err := reportService.Covers(r.Context(), from, to, req.Group)
+		if err != nil {
+			log.Error("failed to build covers report", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to build report"))
+
+			return
+		}
+
+		render.JSON(w, r, resp.OKWithData(stats))
+	}
+}
+
+// Utilisation возвращает загрузку столов по дням: GET /reports/utilisation?from=&to=
+func Utilisation(log *slog.Logger, authClient *grpc.Client, reportService *reportsrv.ReportService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.reports.Utilisation"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		_, from, to, ok := parseRequest(log, authClient, w, r)
+		if !ok {
+			return
+		}
+
+		stats, err := reportService.Utilisation(r.Context(), from, to)
+		if err != nil {
+			log.Error("failed to build utilisation report", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to build report"))
+
+			return
+		}
+
+		render.JSON(w, r, resp.OKWithData(stats))
+	}
+}
+
+// Rates возвращает доли отмен и неявок и среднее время упреждения: GET /reports/rates?from=&to=
+func Rates(log *slog.Logger, authClient *grpc.Client, reportService *reportsrv.ReportService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.reports.Rates"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		_, from, to, ok := parseRequest(log, authClient, w, r)
+		if !ok {
+			return
+		}
+
+		rates, err := reportService.Rates(r.Context(), from, to)
+		if err != nil {
+			log.Error("failed to build rates report", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to build report"))
+
+			return
+		}
+
+		render.JSON(w, r, resp.OKWithData(rates))
+	}
+}
+
+// BusiestTables возвращает самые загруженные столы: GET /reports/tables?from=&to=&limit=
+func BusiestTables(log *slog.Logger, authClient *grpc.Client, reportService *reportsrv.ReportService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.reports.BusiestTables"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		req, from, to, ok := parseRequest(log, authClient, w, r)
+		if !ok {
+			return
+		}
+
+		if req.Limit == 0 {
+			req.Limit = defaultTablesLimit
+		}
+
+		stats, err := reportService.BusiestTables(r.Context(), from, to, req.Limit)
+		if err != nil {
+			log.Error("failed to build tables report", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to build report"))
+
+			return
+		}
+
+		render.JSON(w, r, resp.OKWithData(stats))
+	}
+}
+
+// Ratings возвращает оценки гостей по дням визитов и по столам: GET /reports/ratings?from=&to=
+func Ratings(log *slog.Logger, authClient *grpc.Client, reportService *reportsrv.ReportService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.reports.Ratings"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		_, from, to, ok := parseRequest(log, authClient, w, r)
+		if !ok {
+			return
+		}
+
+		report, err := reportService.Ratings(r.Context(), from, to)
+		if err != nil {
+			log.Error("failed to build ratings report", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to build report"))
+
+			return
+		}
+
+		render.JSON(w, r, resp.OKWithData(report))
+	}
+}
+
+// parseRequest проверяет, что запрос сделал админ, и разбирает период отчёта.
+// Дата to включается в период. При ошибке ответ уже записан и ok == false.
+func parseRequest(
+	log *slog.Logger,
+	authClient *grpc.Client,
+	w http.ResponseWriter,
+	r *http.Request,
+) (req RangeRequest, from, to time.Time, ok bool) {
+	if _, ok := auth.RequireAdmin(log, authClient, w, r); !ok {
+		return req, from, to, false
+	}
+
+	var err error
+
+	query := r.URL.Query()
+
+	req = RangeRequest{
+		From:  query.Get("from"),
+		To:    query.Get("to"),
+		Group: query.Get("group"),
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		req.Limit, err = strconv.Atoi(limit)
+		if err != nil {
+			log.Warn("invalid limit", slog.String("limit", limit))
+
+			render.JSON(w, r, resp.Error("Field Limit is not valid"))
+
+			return req, from, to, false
+		}
+	}
+
+	if err := validator.New().Struct(req); err != nil {
+		validateErr := err.(validator.ValidationErrors)
+
+		log.Error("invalid request", sl.Err(err))
+
+		render.JSON(w, r, resp.ValidationError(validateErr))
+
+		return req, from, to, false
+	}
+
+	from, err = time.Parse(dateLayout, req.From)
+	if err != nil {
+		render.JSON(w, r, resp.Error("Field From must be a date in YYYY-MM-DD format"))
+
+		return req, from, to, false
+	}
+
+	to, err = time.Parse(dateLayout, req.To)
+	if err != nil {
+		render.JSON(w, r, resp.Error("Field To must be a date in YYYY-MM-DD format"))
+
+		return req, from, to, false
+	}
+	to = to.AddDate(0, 0, 1)
+
+	if !to.After(from) || to.Sub(from) > maxRangeDays*24*time.Hour {
+		render.JSON(w, r, resp.Error("Invalid date range"))
+
+		return req, from, to, false
+	}
+
+	return req, from, to, true
+}
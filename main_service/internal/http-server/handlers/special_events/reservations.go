@@ -0,0 +1,170 @@
+package specialevents
+
+import (
+	"errors"
+	"log/slog"
+	specialeventsrv "main_service/internal/http-server/handlers/middleware/special_events"
+	resp "main_service/internal/lib/api/response"
+	"main_service/internal/lib/logger/sl"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator"
+)
+
+// ReserveRequest - сколько мест гость хочет на событии.
+type ReserveRequest struct {
+	Seats int `json:"seats" validate:"required,gt=0,max=10"`
+}
+
+// Reserve резервирует места на событии для текущего пользователя.
+func Reserve(log *slog.Logger, eventService *specialeventsrv.SpecialEventService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.special-events.Reserve"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userID, ok := r.Context().Value(models.ContextKey("uid")).(float64)
+		if !ok || userID <= 0 {
+			log.Error("unauthorized: no userID in context")
+
+			render.JSON(w, r, resp.Error("Unauthorized"))
+
+			return
+		}
+
+		eventID := parseID(r)
+		if eventID == 0 {
+			log.Warn("invalid event id", slog.String("id", chi.URLParam(r, "id")))
+
+			render.JSON(w, r, resp.Error("Invalid event id"))
+
+			return
+		}
+
+		var req ReserveRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to decode request"))
+
+			return
+		}
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			log.Error("invalid request", sl.Err(err))
+
+			render.JSON(w, r, resp.ValidationError(validateErr))
+
+			return
+		}
+
+		reservation, err := eventService.Reserve(r.Context(), eventID, int64(userID), req.Seats)
+		if err != nil {
+			if errors.Is(err, storage.ErrEventNotFound) {
+				render.JSON(w, r, resp.Error("Event not found"))
+
+				return
+			} else if errors.Is(err, storage.ErrEventStarted) {
+				render.JSON(w, r, resp.Error("Event has already started"))
+
+				return
+			} else if errors.Is(err, storage.ErrUserBlocked) {
+				log.Warn("failed to reserve event seats, user is blocked", slog.Int("userID", int(userID)))
+
+				render.JSON(w, r, resp.Error("Booking is not available for this account, please contact the restaurant"))
+
+				return
+			} else if errors.Is(err, storage.ErrEventSoldOut) {
+				log.Info("not enough seats left", slog.Int64("eventID", eventID), slog.Int("seats", req.Seats))
+
+				render.JSON(w, r, resp.Error("Not enough seats left"))
+
+				return
+			} else if errors.Is(err, storage.ErrReservationExists) {
+				render.JSON(w, r, resp.Error("You already have seats for this event"))
+
+				return
+			}
+
+			log.Error("failed to reserve event seats", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to reserve seats"))
+
+			return
+		}
+
+		log.Info("event seats reserved",
+			slog.Int64("eventID", eventID),
+			slog.Int64("reservationID", reservation.ID),
+			slog.Int("seats", reservation.Seats),
+		)
+
+		render.JSON(w, r, resp.OKWithData(reservation))
+	}
+}
+
+// CancelReservation отменяет места текущего пользователя на событии, пока оно не началось.
+func CancelReservation(log *slog.Logger, eventService *specialeventsrv.SpecialEventService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.special-events.CancelReservation"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userID, ok := r.Context().Value(models.ContextKey("uid")).(float64)
+		if !ok || userID <= 0 {
+			log.Error("unauthorized: no userID in context")
+
+			render.JSON(w, r, resp.Error("Unauthorized"))
+
+			return
+		}
+
+		eventID := parseID(r)
+		if eventID == 0 {
+			log.Warn("invalid event id", slog.String("id", chi.URLParam(r, "id")))
+
+			render.JSON(w, r, resp.Error("Invalid event id"))
+
+			return
+		}
+
+		if err := eventService.CancelReservation(r.Context(), eventID, int64(userID)); err != nil {
+			if errors.Is(err, storage.ErrEventNotFound) {
+				render.JSON(w, r, resp.Error("Event not found"))
+
+				return
+			} else if errors.Is(err, storage.ErrEventStarted) {
+				render.JSON(w, r, resp.Error("Event has already started"))
+
+				return
+			} else if errors.Is(err, storage.ErrReservationNotFound) {
+				render.JSON(w, r, resp.Error("You have no seats for this event"))
+
+				return
+			}
+
+			log.Error("failed to cancel event reservation", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to cancel reservation"))
+
+			return
+		}
+
+		log.Info("event reservation cancelled", slog.Int64("eventID", eventID))
+
+		render.JSON(w, r, resp.OK())
+	}
+}
@@ -0,0 +1,219 @@
+package specialevents
+
+import (
+	"errors"
+	"log/slog"
+	"main_service/internal/clients/sso/grpc"
+	specialeventsrv "main_service/internal/http-server/handlers/middleware/special_events"
+	"main_service/internal/lib/api/auth"
+	resp "main_service/internal/lib/api/response"
+	"main_service/internal/lib/logger/sl"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator"
+)
+
+// CreateRequest - новое событие. Price - цена одного места в копейках.
+type CreateRequest struct {
+	Title       string    `json:"title" validate:"required,max=200"`
+	Description string    `json:"description" validate:"max=2000"`
+	StartsAt    time.Time `json:"startsAt" validate:"required"`
+	EndsAt      time.Time `json:"endsAt" validate:"required"`
+	Capacity    int       `json:"capacity" validate:"required,gt=0,max=500"`
+	Price       int64     `json:"price" validate:"gte=0"`
+}
+
+// List возвращает предстоящие события с числом свободных мест.
+func List(log *slog.Logger, eventService *specialeventsrv.SpecialEventService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.special-events.List"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		events, err := eventService.GetUpcomingEvents(r.Context())
+		if err != nil {
+			log.Error("failed to get special events", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to fetch events"))
+
+			return
+		}
+
+		render.JSON(w, r, resp.OKWithData(events))
+	}
+}
+
+// Create добавляет событие. Доступно только админам.
+func Create(log *slog.Logger, authClient *grpc.Client, eventService *specialeventsrv.SpecialEventService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.special-events.Create"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		adminID, ok := auth.RequireAdmin(log, authClient, w, r)
+		if !ok {
+			return
+		}
+
+		var req CreateRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to decode request"))
+
+			return
+		}
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			log.Error("invalid request", sl.Err(err))
+
+			render.JSON(w, r, resp.ValidationError(validateErr))
+
+			return
+		}
+
+		if !req.EndsAt.After(req.StartsAt) {
+			render.JSON(w, r, resp.Error("Field EndsAt must be after StartsAt"))
+
+			return
+		}
+
+		if !req.StartsAt.After(time.Now()) {
+			render.JSON(w, r, resp.Error("Event must start in the future"))
+
+			return
+		}
+
+		event, err := eventService.CreateEvent(r.Context(), models.SpecialEvent{
+			Title:       strings.TrimSpace(req.Title),
+			Description: strings.TrimSpace(req.Description),
+			StartsAt:    req.StartsAt,
+			EndsAt:      req.EndsAt,
+			Capacity:    req.Capacity,
+			Price:       req.Price,
+			CreatedBy:   adminID,
+		})
+		if err != nil {
+			log.Error("failed to create special event", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to create event"))
+
+			return
+		}
+
+		log.Info("special event created", slog.Int64("eventID", event.ID))
+
+		render.JSON(w, r, resp.OKWithData(event))
+	}
+}
+
+// Delete отменяет событие вместе со всеми резервациями. Доступно только админам.
+func Delete(log *slog.Logger, authClient *grpc.Client, eventService *specialeventsrv.SpecialEventService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.special-events.Delete"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		if _, ok := auth.RequireAdmin(log, authClient, w, r); !ok {
+			return
+		}
+
+		id := parseID(r)
+		if id == 0 {
+			log.Warn("invalid event id", slog.String("id", chi.URLParam(r, "id")))
+
+			render.JSON(w, r, resp.Error("Invalid event id"))
+
+			return
+		}
+
+		if err := eventService.CancelEvent(r.Context(), id); err != nil {
+			if errors.Is(err, storage.ErrEventNotFound) {
+				render.JSON(w, r, resp.Error("Event not found"))
+
+				return
+			}
+
+			log.Error("failed to cancel special event", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to cancel event"))
+
+			return
+		}
+
+		log.Info("special event cancelled", slog.Int64("eventID", id))
+
+		render.JSON(w, r, resp.OK())
+	}
+}
+
+// Attendees возвращает событие и список гостей, зарезервировавших места. Доступно только админам.
+func Attendees(log *slog.Logger, authClient *grpc.Client, eventService *specialeventsrv.SpecialEventService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.special-events.Attendees"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		if _, ok := auth.RequireAdmin(log, authClient, w, r); !ok {
+			return
+		}
+
+		id := parseID(r)
+		if id == 0 {
+			log.Warn("invalid event id", slog.String("id", chi.URLParam(r, "id")))
+
+			render.JSON(w, r, resp.Error("Invalid event id"))
+
+			return
+		}
+
+		attendees, err := eventService.GetAttendees(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, storage.ErrEventNotFound) {
+				render.JSON(w, r, resp.Error("Event not found"))
+
+				return
+			}
+
+			log.Error("failed to get event attendees", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to fetch attendees"))
+
+			return
+		}
+
+		render.JSON(w, r, resp.OKWithData(attendees))
+	}
+}
+
+// parseID читает id события из пути. Возвращает 0, если id некорректный.
+func parseID(r *http.Request) int64 {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil || id <= 0 {
+		return 0
+	}
+
+	return id
+}
@@ -0,0 +1,223 @@
+package webhooks
+
+import (
+	"errors"
+	"log/slog"
+	"main_service/internal/clients/sso/grpc"
+	webhooksrv "main_service/internal/http-server/handlers/middleware/webhooks"
+	"main_service/internal/lib/api/auth"
+	resp "main_service/internal/lib/api/response"
+	"main_service/internal/lib/logger/sl"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator"
+)
+
+// CreateRequest - подписка на события броней. Адрес принимается только https, если secret не указан, он генерируется.
+type CreateRequest struct {
+	URL        string   `json:"url" validate:"required,url,startswith=https://,max=2000"`
+	EventTypes []string `json:"eventTypes" validate:"required,min=1,unique,dive,oneof=created cancelled changed"`
+	Secret     string   `json:"secret" validate:"omitempty,min=16,max=200"`
+}
+
+// Create добавляет подписку на события броней и возвращает её вместе с секретом подписи. Доступно только админам.
+func Create(log *slog.Logger, authClient *grpc.Client, webhookService *webhooksrv.WebhookService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.webhooks.Create"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		if _, ok := auth.RequireAdmin(log, authClient, w, r); !ok {
+			return
+		}
+
+		var req CreateRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to decode request"))
+
+			return
+		}
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			log.Error("invalid request", sl.Err(err))
+
+			render.JSON(w, r, resp.ValidationError(validateErr))
+
+			return
+		}
+
+		webhook, err := webhookService.CreateWebhook(r.Context(), models.Webhook{
+			URL:        req.URL,
+			EventTypes: req.EventTypes,
+			Secret:     req.Secret,
+		})
+		if err != nil {
+			log.Error("failed to create webhook", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to create webhook"))
+
+			return
+		}
+
+		log.Info("webhook created", slog.Int64("webhookID", webhook.ID))
+
+		render.JSON(w, r, resp.OKWithData(webhook))
+	}
+}
+
+// List возвращает подписки без секретов. Доступно только админам.
+func List(log *slog.Logger, authClient *grpc.Client, webhookService *webhooksrv.WebhookService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.webhooks.List"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		if _, ok := auth.RequireAdmin(log, authClient, w, r); !ok {
+			return
+		}
+
+		webhooks, err := webhookService.GetWebhooks(r.Context())
+		if err != nil {
+			log.Error("failed to get webhooks", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to fetch webhooks"))
+
+			return
+		}
+
+		render.JSON(w, r, resp.OKWithData(webhooks))
+	}
+}
+
+// Delete удаляет подписку. Доступно только админам.
+func Delete(log *slog.Logger, authClient *grpc.Client, webhookService *webhooksrv.WebhookService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.webhooks.Delete"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		if _, ok := auth.RequireAdmin(log, authClient, w, r); !ok {
+			return
+		}
+
+		id, ok := parseID(log, w, r)
+		if !ok {
+			return
+		}
+
+		if err := webhookService.DeleteWebhook(r.Context(), id); err != nil {
+			renderError(log, w, r, err, "Failed to delete webhook")
+
+			return
+		}
+
+		log.Info("webhook deleted", slog.Int64("webhookID", id))
+
+		render.JSON(w, r, resp.OK())
+	}
+}
+
+// Enable включает подписку, отключённую после неудачных доставок. Доступно только админам.
+func Enable(log *slog.Logger, authClient *grpc.Client, webhookService *webhooksrv.WebhookService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.webhooks.Enable"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		if _, ok := auth.RequireAdmin(log, authClient, w, r); !ok {
+			return
+		}
+
+		id, ok := parseID(log, w, r)
+		if !ok {
+			return
+		}
+
+		if err := webhookService.EnableWebhook(r.Context(), id); err != nil {
+			renderError(log, w, r, err, "Failed to enable webhook")
+
+			return
+		}
+
+		log.Info("webhook enabled", slog.Int64("webhookID", id))
+
+		render.JSON(w, r, resp.OK())
+	}
+}
+
+// Attempts возвращает последние попытки доставки по подписке. Доступно только админам.
+func Attempts(log *slog.Logger, authClient *grpc.Client, webhookService *webhooksrv.WebhookService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.webhooks.Attempts"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		if _, ok := auth.RequireAdmin(log, authClient, w, r); !ok {
+			return
+		}
+
+		id, ok := parseID(log, w, r)
+		if !ok {
+			return
+		}
+
+		attempts, err := webhookService.GetAttempts(r.Context(), id)
+		if err != nil {
+			renderError(log, w, r, err, "Failed to fetch delivery attempts")
+
+			return
+		}
+
+		render.JSON(w, r, resp.OKWithData(attempts))
+	}
+}
+
+func parseID(log *slog.Logger, w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil || id <= 0 {
+		log.Warn("invalid webhook id", slog.String("id", chi.URLParam(r, "id")))
+
+		render.JSON(w, r, resp.Error("Invalid webhook id"))
+
+		return 0, false
+	}
+
+	return id, true
+}
+
+func renderError(log *slog.Logger, w http.ResponseWriter, r *http.Request, err error, msg string) {
+	if errors.Is(err, storage.ErrWebhookNotFound) {
+		render.JSON(w, r, resp.Error("Webhook not found"))
+
+		return
+	}
+
+	log.Error(msg, sl.Err(err))
+
+	render.JSON(w, r, resp.Error(msg))
+}
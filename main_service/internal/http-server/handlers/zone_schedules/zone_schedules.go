@@ -0,0 +1,200 @@
+package zoneschedules
+
+import (
+	"errors"
+	"log/slog"
+	"main_service/internal/clients/sso/grpc"
+	tablesrv "main_service/internal/http-server/handlers/middleware/tables"
+	"main_service/internal/lib/api/auth"
+	resp "main_service/internal/lib/api/response"
+	"main_service/internal/lib/logger/sl"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator"
+)
+
+const (
+	dateLayout = "2006-01-02"
+	timeLayout = "15:04"
+)
+
+// CreateRequest - период, когда зал открыт: даты включительно в формате YYYY-MM-DD,
+// время открытия и закрытия в формате HH:MM.
+type CreateRequest struct {
+	StartsOn string `json:"startsOn" validate:"required,len=10"`
+	EndsOn   string `json:"endsOn" validate:"required,len=10"`
+	OpensAt  string `json:"opensAt" validate:"required,len=5"`
+	ClosesAt string `json:"closesAt" validate:"required,len=5"`
+}
+
+// Create добавляет залу расписание. С первым расписанием зал перестаёт быть открытым всегда:
+// его столы можно бронировать только в пределах расписаний. Доступно только админам.
+func Create(log *slog.Logger, authClient *grpc.Client, tableService *tablesrv.TableService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.zone-schedules.Create"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		adminID, ok := auth.RequireAdmin(log, authClient, w, r)
+		if !ok {
+			return
+		}
+
+		var req CreateRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to decode request"))
+
+			return
+		}
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			log.Error("invalid request", sl.Err(err))
+
+			render.JSON(w, r, resp.ValidationError(validateErr))
+
+			return
+		}
+
+		startsOn, err1 := time.Parse(dateLayout, req.StartsOn)
+		endsOn, err2 := time.Parse(dateLayout, req.EndsOn)
+		if err1 != nil || err2 != nil {
+			render.JSON(w, r, resp.Error("Fields StartsOn and EndsOn must be dates in YYYY-MM-DD format"))
+
+			return
+		}
+
+		if endsOn.Before(startsOn) {
+			render.JSON(w, r, resp.Error("Field EndsOn must not be before StartsOn"))
+
+			return
+		}
+
+		opensAt, err1 := time.Parse(timeLayout, req.OpensAt)
+		closesAt, err2 := time.Parse(timeLayout, req.ClosesAt)
+		if err1 != nil || err2 != nil {
+			render.JSON(w, r, resp.Error("Fields OpensAt and ClosesAt must be times in HH:MM format"))
+
+			return
+		}
+
+		if !closesAt.After(opensAt) {
+			render.JSON(w, r, resp.Error("Field ClosesAt must be after OpensAt"))
+
+			return
+		}
+
+		zone := chi.URLParam(r, "zone")
+
+		schedule, err := tableService.CreateZoneSchedule(r.Context(), models.ZoneSchedule{
+			Zone:      zone,
+			StartsOn:  req.StartsOn,
+			EndsOn:    req.EndsOn,
+			OpensAt:   req.OpensAt,
+			ClosesAt:  req.ClosesAt,
+			CreatedBy: adminID,
+		})
+		if err != nil {
+			if errors.Is(err, storage.ErrZoneNotFound) {
+				log.Warn("failed to create zone schedule, zone not found", slog.String("zone", zone))
+
+				render.JSON(w, r, resp.Error("Zone not found"))
+
+				return
+			}
+
+			log.Error("failed to create zone schedule", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to create zone schedule"))
+
+			return
+		}
+
+		log.Info("zone schedule created", slog.Int64("scheduleID", schedule.ID), slog.String("zone", zone))
+
+		render.JSON(w, r, resp.OKWithData(schedule))
+	}
+}
+
+// List возвращает расписания всех залов. Доступно только админам.
+func List(log *slog.Logger, authClient *grpc.Client, tableService *tablesrv.TableService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.zone-schedules.List"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		if _, ok := auth.RequireAdmin(log, authClient, w, r); !ok {
+			return
+		}
+
+		schedules, err := tableService.GetZoneSchedules(r.Context())
+		if err != nil {
+			log.Error("failed to get zone schedules", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to fetch zone schedules"))
+
+			return
+		}
+
+		render.JSON(w, r, resp.OKWithData(schedules))
+	}
+}
+
+// Delete удаляет расписание зала. Доступно только админам.
+func Delete(log *slog.Logger, authClient *grpc.Client, tableService *tablesrv.TableService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.zone-schedules.Delete"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		if _, ok := auth.RequireAdmin(log, authClient, w, r); !ok {
+			return
+		}
+
+		id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil || id <= 0 {
+			log.Warn("invalid zone schedule id", slog.String("id", chi.URLParam(r, "id")))
+
+			render.JSON(w, r, resp.Error("Invalid zone schedule id"))
+
+			return
+		}
+
+		if err := tableService.DeleteZoneSchedule(r.Context(), id); err != nil {
+			if errors.Is(err, storage.ErrScheduleNotFound) {
+				render.JSON(w, r, resp.Error("Zone schedule not found"))
+
+				return
+			}
+
+			log.Error("failed to delete zone schedule", sl.Err(err))
+
+			render.JSON(w, r, resp.Error("Failed to delete zone schedule"))
+
+			return
+		}
+
+		log.Info("zone schedule deleted", slog.Int64("scheduleID", id))
+
+		render.JSON(w, r, resp.OK())
+	}
+}
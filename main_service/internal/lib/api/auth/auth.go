@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	resp "main_service/internal/lib/api/response"
+	"main_service/internal/lib/logger/sl"
+	"main_service/internal/models"
+
+	"github.com/go-chi/render"
+)
+
+// RoleChecker узнаёт роль пользователя в SSO.
+type RoleChecker interface {
+	IsAdmin(ctx context.Context, userID int64) (bool, error)
+}
+
+// Actor определяет, кто сделал запрос: админ или гость. При ошибке ответ уже записан и ok == false.
+func Actor(log *slog.Logger, authClient RoleChecker, w http.ResponseWriter, r *http.Request) (models.Actor, bool) {
+	userID, ok := r.Context().Value(models.ContextKey("uid")).(float64)
+	if !ok || userID <= 0 {
+		log.Error("unauthorized: no userID in context")
+
+		render.JSON(w, r, resp.Error("Unauthorized"))
+
+		return models.Actor{}, false
+	}
+
+	isAdmin, err := authClient.IsAdmin(r.Context(), int64(userID))
+	if err != nil {
+		log.Error("failed to check user role", sl.Err(err))
+
+		render.JSON(w, r, resp.Error("Failed to check user role"))
+
+		return models.Actor{}, false
+	}
+
+	actor := models.Actor{ID: int64(userID), Role: models.RoleCustomer}
+	if isAdmin {
+		actor.Role = models.RoleAdmin
+	}
+
+	return actor, true
+}
+
+// RequireAdmin проверяет, что запрос сделал админ, и возвращает его id. При отказе ответ уже записан и ok == false.
+func RequireAdmin(log *slog.Logger, authClient RoleChecker, w http.ResponseWriter, r *http.Request) (int64, bool) {
+	actor, ok := Actor(log, authClient, w, r)
+	if !ok {
+		return 0, false
+	}
+
+	if actor.Role != models.RoleAdmin {
+		log.Warn("customer attempted an admin action",
+			slog.Int64("userID", actor.ID),
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+		)
+
+		render.JSON(w, r, resp.Error("Permisson denied"))
+
+		return 0, false
+	}
+
+	return actor.ID, true
+}
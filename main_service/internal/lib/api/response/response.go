@@ -38,6 +38,15 @@ func Error(msg string) Response {
 	}
 }
 
+// ErrorWithData - ошибка с дополнительными данными, например вариантами, которые можно предложить вместо запрошенного.
+func ErrorWithData(msg string, data interface{}) Response {
+	return Response{
+		Status: StatusError,
+		Data:   data,
+		Error:  msg,
+	}
+}
+
 func ValidationError(errs validator.ValidationErrors) Response {
 	var errMsgs []string
 
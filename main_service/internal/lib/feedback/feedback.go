@@ -0,0 +1,58 @@
+package feedback
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidSignature = errors.New("feedback link signature is invalid")
+	ErrLinkExpired      = errors.New("feedback link has expired")
+)
+
+// Sign возвращает подпись ссылки на отзыв: HMAC-SHA256 от "<bookingID>.<expires>" в hex.
+// Срок действия входит в подпись, поэтому его нельзя продлить, не зная секрета.
+func Sign(secret string, bookingID int64, expires time.Time) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(bookingID, 10)))
+	mac.Write([]byte("."))
+	mac.Write([]byte(strconv.FormatInt(expires.Unix(), 10)))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify проверяет подпись ссылки на отзыв и что срок её действия не истёк к now.
+func Verify(secret string, bookingID int64, expires time.Time, signature string, now time.Time) error {
+	if !hmac.Equal([]byte(Sign(secret, bookingID, expires)), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+
+	if !now.Before(expires) {
+		return ErrLinkExpired
+	}
+
+	return nil
+}
+
+// Link собирает ссылку на страницу отзыва о визите по брони bookingID вида
+// <pageURL>?booking_id=<bookingID>&expires=<unix>&sig=<подпись>. Страница показывает гостю форму
+// и отправляет её на POST /feedback/<bookingID>?expires=<unix>&sig=<подпись>.
+func Link(pageURL, secret string, bookingID int64, expires time.Time) string {
+	query := url.Values{}
+	query.Set("booking_id", strconv.FormatInt(bookingID, 10))
+	query.Set("expires", strconv.FormatInt(expires.Unix(), 10))
+	query.Set("sig", Sign(secret, bookingID, expires))
+
+	separator := "?"
+	if strings.Contains(pageURL, "?") {
+		separator = "&"
+	}
+
+	return pageURL + separator + query.Encode()
+}
@@ -0,0 +1,68 @@
+package feedback
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVerify(t *testing.T) {
+	const secret = "secret"
+
+	now := time.Date(2025, 9, 20, 12, 0, 0, 0, time.UTC)
+	expires := now.Add(24 * time.Hour)
+	signature := Sign(secret, 42, expires)
+
+	tests := []struct {
+		name      string
+		bookingID int64
+		expires   time.Time
+		signature string
+		now       time.Time
+		want      error
+	}{
+		{"valid", 42, expires, signature, now, nil},
+		{"other booking", 43, expires, signature, now, ErrInvalidSignature},
+		{"extended expiry", 42, expires.Add(time.Hour), signature, now, ErrInvalidSignature},
+		{"wrong secret", 42, expires, Sign("other", 42, expires), now, ErrInvalidSignature},
+		{"expired", 42, expires, signature, expires, ErrLinkExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Verify(secret, tt.bookingID, tt.expires, tt.signature, tt.now); !errors.Is(err, tt.want) {
+				t.Errorf("Verify() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLink(t *testing.T) {
+	expires := time.Date(2025, 9, 21, 12, 0, 0, 0, time.UTC)
+
+	link := Link("https://example.com/feedback", "secret", 42, expires)
+
+	u, err := url.Parse(link)
+	if err != nil {
+		t.Fatalf("url.Parse() error = %v", err)
+	}
+
+	if !strings.HasPrefix(link, "https://example.com/feedback?") {
+		t.Errorf("Link() = %q, want prefix https://example.com/feedback?", link)
+	}
+
+	if got := u.Query().Get("booking_id"); got != "42" {
+		t.Errorf("booking_id = %q, want 42", got)
+	}
+
+	if got := u.Query().Get("expires"); got != strconv.FormatInt(expires.Unix(), 10) {
+		t.Errorf("expires = %q, want %d", got, expires.Unix())
+	}
+
+	if err := Verify("secret", 42, expires, u.Query().Get("sig"), expires.Add(-time.Minute)); err != nil {
+		t.Errorf("Verify() for generated link = %v", err)
+	}
+}
@@ -0,0 +1,142 @@
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	// utcLayout - время в UTC по RFC 5545. Начало и конец события тоже пишутся в UTC: "плавающее" время
+	// без зоны клиент показал бы по часам подписчика, а не ресторана.
+	utcLayout = "20060102T150405Z"
+
+	// maxLineOctets - максимальная длина строки без переноса по RFC 5545.
+	maxLineOctets = 75
+)
+
+// Event - событие календаря. Start и End должны быть моментами времени с правильным поясом,
+// в ленту они попадают переведёнными в UTC.
+type Event struct {
+	UID         string
+	Start       time.Time
+	End         time.Time
+	Summary     string
+	Description string
+	Location    string
+}
+
+type Calendar struct {
+	Name string
+	// RefreshInterval подсказывает клиенту, как часто перечитывать ленту. 0 - не указывать.
+	RefreshInterval time.Duration
+	Events          []Event
+}
+
+// Write сериализует календарь в формат iCalendar (RFC 5545).
+func Write(w io.Writer, cal Calendar) error {
+	bw := bufio.NewWriter(w)
+	now := time.Now().UTC()
+
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//restaurant//booking//RU",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+	}
+
+	if cal.Name != "" {
+		lines = append(lines, "X-WR-CALNAME:"+escape(cal.Name))
+	}
+
+	if cal.RefreshInterval > 0 {
+		interval := duration(cal.RefreshInterval)
+		lines = append(lines,
+			"REFRESH-INTERVAL;VALUE=DURATION:"+interval,
+			"X-PUBLISHED-TTL:"+interval,
+		)
+	}
+
+	for _, e := range cal.Events {
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			"UID:"+escape(e.UID),
+			"DTSTAMP:"+now.Format(utcLayout),
+			"DTSTART:"+e.Start.UTC().Format(utcLayout),
+			"DTEND:"+e.End.UTC().Format(utcLayout),
+			"SUMMARY:"+escape(e.Summary),
+		)
+
+		if e.Description != "" {
+			lines = append(lines, "DESCRIPTION:"+escape(e.Description))
+		}
+
+		if e.Location != "" {
+			lines = append(lines, "LOCATION:"+escape(e.Location))
+		}
+
+		lines = append(lines, "END:VEVENT")
+	}
+
+	lines = append(lines, "END:VCALENDAR")
+
+	for _, line := range lines {
+		if _, err := bw.WriteString(fold(line)); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+// duration форматирует длительность с точностью до минут, например PT1H30M.
+func duration(d time.Duration) string {
+	hours := int(d.Hours())
+	minutes := int(d.Minutes()) % 60
+
+	s := "PT"
+	if hours > 0 {
+		s += fmt.Sprintf("%dH", hours)
+	}
+	if minutes > 0 || hours == 0 {
+		s += fmt.Sprintf("%dM", minutes)
+	}
+
+	return s
+}
+
+// escape экранирует спецсимволы в текстовых значениях.
+func escape(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
+
+// fold переносит строку длиннее 75 октетов и завершает её CRLF.
+// Перенос не разрывает многобайтовые символы UTF-8.
+func fold(line string) string {
+	var b strings.Builder
+
+	n := 0
+	for _, r := range line {
+		size := len(string(r))
+		if n+size > maxLineOctets {
+			b.WriteString("\r\n ")
+			n = 1
+		}
+
+		b.WriteRune(r)
+		n += size
+	}
+
+	b.WriteString("\r\n")
+
+	return b.String()
+}
@@ -0,0 +1,80 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWrite(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	if err != nil {
+		t.Fatalf("time.LoadLocation() error = %v", err)
+	}
+
+	start := time.Date(2025, 9, 5, 19, 30, 0, 0, moscow)
+
+	var buf bytes.Buffer
+	err = Write(&buf, Calendar{
+		Name:            "Брони",
+		RefreshInterval: 15 * time.Minute,
+		Events: []Event{{
+			UID:         "booking-1@restaurant",
+			Start:       start,
+			End:         start.Add(2 * time.Hour),
+			Summary:     "Стол 3, Иван Петров; 4 гостя",
+			Description: "line1\nline2",
+		}},
+	})
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	out := buf.String()
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"REFRESH-INTERVAL;VALUE=DURATION:PT15M\r\n",
+		"DTSTART:20250905T163000Z\r\n",
+		"DTEND:20250905T183000Z\r\n",
+		`SUMMARY:Стол 3\, Иван Петров\; 4 гостя` + "\r\n",
+		`DESCRIPTION:line1\nline2` + "\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output does not contain %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestFold(t *testing.T) {
+	line := "DESCRIPTION:" + strings.Repeat("ж", 60)
+
+	folded := fold(line)
+
+	for _, l := range strings.Split(strings.TrimSuffix(folded, "\r\n"), "\r\n") {
+		if len(l) > maxLineOctets {
+			t.Errorf("line is %d octets, want at most %d", len(l), maxLineOctets)
+		}
+	}
+
+	unfolded := strings.ReplaceAll(strings.TrimSuffix(folded, "\r\n"), "\r\n ", "")
+	if unfolded != line {
+		t.Errorf("unfolded line = %q, want %q", unfolded, line)
+	}
+}
+
+func TestDuration(t *testing.T) {
+	tests := map[time.Duration]string{
+		15 * time.Minute: "PT15M",
+		time.Hour:        "PT1H",
+		90 * time.Minute: "PT1H30M",
+	}
+
+	for d, want := range tests {
+		if got := duration(d); got != want {
+			t.Errorf("duration(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package pacing
+
+import (
+	"time"
+
+	"main_service/internal/config"
+	"main_service/internal/lib/turntime"
+)
+
+// Slot возвращает начало интервала кухни, в который попадает время прихода at.
+// Интервалы отсчитываются от полуночи по часам ресторана.
+func Slot(at time.Time, interval time.Duration) time.Time {
+	midnight := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, at.Location())
+
+	return midnight.Add(at.Sub(midnight).Truncate(interval))
+}
+
+// Limit возвращает ограничение для части дня, в которую попадает at. Нулевое ограничение - без лимита.
+func Limit(cfg config.Pacing, dayParts []config.DayPart, at time.Time) config.PacingLimit {
+	dayPart := turntime.DayPart(dayParts, at)
+
+	for _, l := range cfg.Limits {
+		if l.DayPart == "" || l.DayPart == dayPart {
+			return l
+		}
+	}
+
+	return config.PacingLimit{}
+}
+
+// Fits проверяет, что в интервал, где уже ожидается covers гостей по bookings броням,
+// можно принять ещё компанию из partySize гостей.
+func Fits(limit config.PacingLimit, covers, bookings, partySize int) bool {
+	if limit.MaxCovers > 0 && covers+partySize > limit.MaxCovers {
+		return false
+	}
+
+	if limit.MaxBookings > 0 && bookings+1 > limit.MaxBookings {
+		return false
+	}
+
+	return true
+}
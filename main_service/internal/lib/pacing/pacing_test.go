@@ -0,0 +1,53 @@
+package pacing
+
+import (
+	"testing"
+	"time"
+
+	"main_service/internal/config"
+)
+
+func TestSlot(t *testing.T) {
+	at := time.Date(2025, 9, 12, 19, 37, 0, 0, time.FixedZone("MSK", 3*60*60))
+
+	want := time.Date(2025, 9, 12, 19, 30, 0, 0, at.Location())
+	if got := Slot(at, 15*time.Minute); !got.Equal(want) {
+		t.Errorf("Slot() = %v, want %v", got, want)
+	}
+}
+
+func TestLimitAndFits(t *testing.T) {
+	dayParts := []config.DayPart{{Name: "dinner", From: "16:00", To: "24:00"}}
+	cfg := config.Pacing{
+		Interval: 15 * time.Minute,
+		Limits:   []config.PacingLimit{{DayPart: "dinner", MaxCovers: 10, MaxBookings: 3}},
+	}
+
+	lunch := Limit(cfg, dayParts, time.Date(2025, 9, 12, 13, 0, 0, 0, time.UTC))
+	if !Fits(lunch, 100, 100, 8) {
+		t.Error("lunch has no limit, party should fit")
+	}
+
+	dinner := Limit(cfg, dayParts, time.Date(2025, 9, 12, 19, 0, 0, 0, time.UTC))
+
+	tests := []struct {
+		name      string
+		covers    int
+		bookings  int
+		partySize int
+		want      bool
+	}{
+		{"empty slot", 0, 0, 4, true},
+		{"exactly at covers limit", 6, 1, 4, true},
+		{"over covers limit", 7, 1, 4, false},
+		{"over bookings limit", 4, 3, 2, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Fits(dinner, tt.covers, tt.bookings, tt.partySize); got != tt.want {
+				t.Errorf("Fits() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
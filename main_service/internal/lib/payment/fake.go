@@ -0,0 +1,107 @@
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// HeaderSignature - заголовок с подписью уведомления провайдера: hex HMAC-SHA256 тела секретом.
+const HeaderSignature = "X-Payment-Signature"
+
+// Fake - платёжный провайдер в памяти для локального запуска и тестов. Деньги никуда не списываются:
+// платёж считается оплаченным, когда на callback приходит подписанное секретом уведомление.
+type Fake struct {
+	mu       sync.Mutex
+	secret   string
+	seq      int
+	payments map[string]*FakePayment
+}
+
+// FakePayment - платёж, созданный у фейкового провайдера.
+type FakePayment struct {
+	Request
+	Refunded int64
+}
+
+func NewFake(secret string) *Fake {
+	return &Fake{
+		secret:   secret,
+		payments: make(map[string]*FakePayment),
+	}
+}
+
+func (f *Fake) CreatePayment(ctx context.Context, req Request) (Payment, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.seq++
+	id := fmt.Sprintf("fake_%d", f.seq)
+	f.payments[id] = &FakePayment{Request: req}
+
+	return Payment{
+		ID:  id,
+		URL: "https://payments.example/fake/" + id,
+	}, nil
+}
+
+func (f *Fake) Refund(ctx context.Context, paymentID string, amount int64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	p, ok := f.payments[paymentID]
+	if !ok {
+		return ErrUnknownPayment
+	}
+
+	if p.Refunded+amount > p.Amount {
+		return ErrRefundTooLarge
+	}
+	p.Refunded += amount
+
+	return nil
+}
+
+func (f *Fake) ParseCallback(body []byte, header http.Header) (Callback, error) {
+	if !hmac.Equal([]byte(sign(f.secret, body)), []byte(header.Get(HeaderSignature))) {
+		return Callback{}, ErrInvalidSignature
+	}
+
+	var cb Callback
+	if err := json.Unmarshal(body, &cb); err != nil {
+		return Callback{}, err
+	}
+
+	return cb, nil
+}
+
+// Sign подписывает тело уведомления так же, как это делал бы провайдер.
+func (f *Fake) Sign(body []byte) string {
+	return sign(f.secret, body)
+}
+
+// Payment возвращает платёж по id, чтобы тесты могли проверить сумму и возвраты.
+func (f *Fake) Payment(id string) (FakePayment, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	p, ok := f.payments[id]
+	if !ok {
+		return FakePayment{}, false
+	}
+
+	return *p, true
+}
+
+// sign возвращает hex HMAC-SHA256 тела секретом - подпись уведомления провайдера.
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
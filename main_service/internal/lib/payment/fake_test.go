@@ -0,0 +1,51 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestFakeCallbackSignature(t *testing.T) {
+	f := NewFake("secret")
+	body := []byte(`{"paymentId":"fake_1","status":"succeeded"}`)
+
+	header := http.Header{}
+	header.Set(HeaderSignature, f.Sign(body))
+
+	cb, err := f.ParseCallback(body, header)
+	if err != nil {
+		t.Fatalf("ParseCallback() error = %v", err)
+	}
+
+	if cb.PaymentID != "fake_1" || cb.Status != StatusSucceeded {
+		t.Errorf("unexpected callback: %+v", cb)
+	}
+
+	header.Set(HeaderSignature, NewFake("other").Sign(body))
+	if _, err := f.ParseCallback(body, header); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("ParseCallback() with foreign signature error = %v, want %v", err, ErrInvalidSignature)
+	}
+}
+
+func TestFakeRefund(t *testing.T) {
+	f := NewFake("secret")
+
+	p, err := f.CreatePayment(context.Background(), Request{BookingID: 1, Amount: 5000, Currency: "RUB"})
+	if err != nil {
+		t.Fatalf("CreatePayment() error = %v", err)
+	}
+
+	if err := f.Refund(context.Background(), p.ID, 5000); err != nil {
+		t.Fatalf("Refund() error = %v", err)
+	}
+
+	if err := f.Refund(context.Background(), p.ID, 1); !errors.Is(err, ErrRefundTooLarge) {
+		t.Errorf("second Refund() error = %v, want %v", err, ErrRefundTooLarge)
+	}
+
+	if got, _ := f.Payment(p.ID); got.Refunded != 5000 {
+		t.Errorf("refunded = %d, want 5000", got.Refunded)
+	}
+}
@@ -0,0 +1,53 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// Результаты оплаты в уведомлении провайдера.
+const (
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid payment callback signature")
+	ErrUnknownPayment   = errors.New("payment is unknown to the provider")
+	ErrRefundTooLarge   = errors.New("refund exceeds the paid amount")
+	// ErrEmptySecret возвращается, если провайдеру не передан секрет для проверки уведомлений.
+	ErrEmptySecret = errors.New("payment callback secret is empty")
+)
+
+// Request - платёж, который нужно создать у провайдера. Amount - в минимальных единицах валюты.
+type Request struct {
+	BookingID   int64
+	Amount      int64
+	Currency    string
+	Description string
+	ExpiresAt   time.Time
+}
+
+// Payment - созданный у провайдера платёж: его id и ссылка, по которой гость оплачивает депозит.
+type Payment struct {
+	ID  string
+	URL string
+}
+
+// Callback - уведомление провайдера о результате оплаты.
+type Callback struct {
+	PaymentID string `json:"paymentId"`
+	Status    string `json:"status"`
+}
+
+// Provider - платёжный провайдер, через который принимаются и возвращаются депозиты.
+type Provider interface {
+	// CreatePayment создаёт платёж и возвращает ссылку на оплату.
+	CreatePayment(ctx context.Context, req Request) (Payment, error)
+	// Refund возвращает гостю amount по оплаченному платежу.
+	Refund(ctx context.Context, paymentID string, amount int64) error
+	// ParseCallback проверяет подпись уведомления и возвращает результат оплаты.
+	ParseCallback(body []byte, header http.Header) (Callback, error)
+}
@@ -0,0 +1,33 @@
+package promo
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"main_service/internal/models"
+)
+
+// Normalize приводит промокод к виду, в котором он хранится: без пробелов по краям и в верхнем регистре.
+func Normalize(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Applies проверяет, что промокод включён и действует для визита в at: время попадает в период действия,
+// а день недели - в разрешённые дни, если они заданы.
+func Applies(code models.PromoCode, at time.Time) bool {
+	if !code.IsActive || at.Before(code.ValidFrom) || !at.Before(code.ValidUntil) {
+		return false
+	}
+
+	if len(code.Weekdays) == 0 {
+		return true
+	}
+
+	return slices.Contains(code.Weekdays, strings.ToLower(at.Weekday().String()))
+}
+
+// Exhausted проверяет, что промокод уже использован максимальное число раз.
+func Exhausted(code models.PromoCode) bool {
+	return code.MaxUses != nil && code.UsedCount >= *code.MaxUses
+}
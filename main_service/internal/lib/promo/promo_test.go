@@ -0,0 +1,54 @@
+package promo
+
+import (
+	"testing"
+	"time"
+
+	"main_service/internal/models"
+)
+
+func TestApplies(t *testing.T) {
+	code := models.PromoCode{
+		Code:       "DESSERT",
+		ValidFrom:  time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC),
+		ValidUntil: time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC),
+		Weekdays:   []string{"tuesday"},
+		IsActive:   true,
+	}
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want bool
+	}{
+		{"tuesday in period", time.Date(2025, 9, 16, 19, 0, 0, 0, time.UTC), true},
+		{"wednesday in period", time.Date(2025, 9, 17, 19, 0, 0, 0, time.UTC), false},
+		{"before period", time.Date(2025, 8, 26, 19, 0, 0, 0, time.UTC), false},
+		{"end of period is exclusive", code.ValidUntil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Applies(code, tt.at); got != tt.want {
+				t.Errorf("Applies() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	code.IsActive = false
+	if Applies(code, time.Date(2025, 9, 16, 19, 0, 0, 0, time.UTC)) {
+		t.Error("Applies() = true for inactive code")
+	}
+}
+
+func TestExhausted(t *testing.T) {
+	one := 1
+
+	if Exhausted(models.PromoCode{UsedCount: 100}) {
+		t.Error("code without limit is exhausted")
+	}
+
+	if !Exhausted(models.PromoCode{MaxUses: &one, UsedCount: 1}) {
+		t.Error("single-use voucher is not exhausted after use")
+	}
+}
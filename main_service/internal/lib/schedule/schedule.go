@@ -0,0 +1,52 @@
+package schedule
+
+import (
+	"time"
+
+	"main_service/internal/models"
+)
+
+const (
+	dateLayout = "2006-01-02"
+	timeLayout = "15:04"
+)
+
+// IsOpen проверяет, открыт ли зал zone на весь визит [from, to). Зал без расписаний открыт всегда,
+// иначе визит должен целиком попадать в одно из его расписаний и не переходить через полночь.
+func IsOpen(schedules []models.ZoneSchedule, zone string, from, to time.Time) bool {
+	scheduled := false
+	for _, s := range schedules {
+		if s.Zone != zone {
+			continue
+		}
+		scheduled = true
+
+		if covers(s, from, to) {
+			return true
+		}
+	}
+
+	return !scheduled
+}
+
+// ClosedTables возвращает столы, залы которых закрыты хотя бы на часть визита [from, to).
+func ClosedTables(tables []models.Table, schedules []models.ZoneSchedule, from, to time.Time) []int16 {
+	closed := []int16{}
+	for _, t := range tables {
+		if !IsOpen(schedules, t.Zone, from, to) {
+			closed = append(closed, t.ID)
+		}
+	}
+
+	return closed
+}
+
+// covers сравнивает даты и время строками: форматы YYYY-MM-DD и HH:MM упорядочены так же, как их значения.
+func covers(s models.ZoneSchedule, from, to time.Time) bool {
+	day := from.Format(dateLayout)
+	if day < s.StartsOn || day > s.EndsOn || to.Format(dateLayout) != day {
+		return false
+	}
+
+	return from.Format(timeLayout) >= s.OpensAt && to.Format(timeLayout) <= s.ClosesAt
+}
@@ -0,0 +1,44 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"main_service/internal/models"
+)
+
+func TestIsOpen(t *testing.T) {
+	schedules := []models.ZoneSchedule{{
+		Zone:     "terrace",
+		StartsOn: "2025-05-01",
+		EndsOn:   "2025-09-30",
+		OpensAt:  "17:00",
+		ClosesAt: "23:00",
+	}}
+
+	at := func(month time.Month, day, hour int) time.Time {
+		return time.Date(2025, month, day, hour, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name string
+		zone string
+		from time.Time
+		want bool
+	}{
+		{"zone without schedules", "main", at(time.January, 10, 12), true},
+		{"summer evening", "terrace", at(time.July, 10, 19), true},
+		{"last season day", "terrace", at(time.September, 30, 21), true},
+		{"summer afternoon", "terrace", at(time.July, 10, 15), false},
+		{"visit ends after closing", "terrace", at(time.July, 10, 22), false},
+		{"out of season", "terrace", at(time.October, 1, 19), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsOpen(schedules, tt.zone, tt.from, tt.from.Add(2*time.Hour)); got != tt.want {
+				t.Errorf("IsOpen() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package turntime
+
+import (
+	"time"
+
+	"main_service/internal/config"
+)
+
+// timeLayout - формат границ частей дня. Строки HH:MM упорядочены так же, как время, которое они задают.
+const timeLayout = "15:04"
+
+// Duration возвращает, сколько длится визит компании partySize с началом в at:
+// по первому подходящему правилу TurnTimes или BookingDuration, если ни одно не подошло.
+func Duration(restaurant config.Restaurant, partySize int16, at time.Time) time.Duration {
+	dayPart := DayPart(restaurant.DayParts, at)
+
+	for _, rule := range restaurant.TurnTimes {
+		if partySize < rule.MinPartySize || (rule.MaxPartySize > 0 && partySize > rule.MaxPartySize) {
+			continue
+		}
+
+		if rule.DayPart != "" && rule.DayPart != dayPart {
+			continue
+		}
+
+		return rule.Duration
+	}
+
+	return restaurant.BookingDuration
+}
+
+// DayPart возвращает название части дня, в которую попадает время at, или пустую строку.
+func DayPart(parts []config.DayPart, at time.Time) string {
+	clock := at.Format(timeLayout)
+
+	for _, p := range parts {
+		if clock >= p.From && clock < p.To {
+			return p.Name
+		}
+	}
+
+	return ""
+}
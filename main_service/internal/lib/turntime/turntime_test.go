@@ -0,0 +1,47 @@
+package turntime
+
+import (
+	"testing"
+	"time"
+
+	"main_service/internal/config"
+)
+
+func TestDuration(t *testing.T) {
+	restaurant := config.Restaurant{
+		BookingDuration: 2 * time.Hour,
+		DayParts: []config.DayPart{
+			{Name: "lunch", From: "11:00", To: "16:00"},
+			{Name: "dinner", From: "16:00", To: "24:00"},
+		},
+		TurnTimes: []config.TurnTime{
+			{MaxPartySize: 2, Duration: 90 * time.Minute},
+			{MinPartySize: 3, MaxPartySize: 4, DayPart: "lunch", Duration: 75 * time.Minute},
+			{MinPartySize: 8, Duration: 3 * time.Hour},
+		},
+	}
+
+	lunch := time.Date(2025, 9, 12, 13, 0, 0, 0, time.UTC)
+	dinner := time.Date(2025, 9, 12, 19, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		partySize int16
+		at        time.Time
+		want      time.Duration
+	}{
+		{"two-top", 2, dinner, 90 * time.Minute},
+		{"four at lunch", 4, lunch, 75 * time.Minute},
+		{"four at dinner falls back to default", 4, dinner, 2 * time.Hour},
+		{"party of eight", 8, lunch, 3 * time.Hour},
+		{"party of six falls back to default", 6, dinner, 2 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Duration(restaurant, tt.partySize, tt.at); got != tt.want {
+				t.Errorf("Duration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
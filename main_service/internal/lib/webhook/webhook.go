@@ -0,0 +1,205 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// Заголовки, которые получатель использует для проверки запроса.
+const (
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+const signaturePrefix = "sha256="
+
+// Request - одна попытка доставки события на адрес подписки.
+type Request struct {
+	URL        string
+	Secret     string
+	DeliveryID int64
+	Event      string
+	Body       []byte
+}
+
+// ErrForbiddenAddress возвращается, если адрес подписки ведёт во внутреннюю сеть или не использует https.
+var ErrForbiddenAddress = errors.New("webhook address is not allowed")
+
+// Client отправляет события подписчикам.
+type Client struct {
+	http      *http.Client
+	httpsOnly bool
+}
+
+// NewClient возвращает клиент для рабочих подписок. Адрес подписки задаёт админ, поэтому клиент
+// не даёт сделать из вебхука запрос во внутреннюю сеть: принимает только https, проверяет IP,
+// к которому реально подключается (после резолва DNS), и не ходит по редиректам.
+func NewClient(timeout time.Duration) *Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+
+			if !isPublic(addrPort.Addr()) {
+				return fmt.Errorf("%w: %s", ErrForbiddenAddress, addrPort.Addr())
+			}
+
+			return nil
+		},
+	}
+
+	return &Client{
+		http: &http.Client{
+			Timeout: timeout,
+			Transport: &http.Transport{
+				// Без прокси: иначе проверялся бы адрес прокси, а не получателя.
+				Proxy:               nil,
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: timeout,
+				MaxIdleConns:        100,
+				IdleConnTimeout:     90 * time.Second,
+			},
+			CheckRedirect: noRedirects,
+		},
+		httpsOnly: true,
+	}
+}
+
+// NewLocalClient возвращает клиент без проверки адресов - для тестов и локальной разработки,
+// когда получатель слушает на localhost. Редиректы он тоже не выполняет.
+func NewLocalClient(timeout time.Duration) *Client {
+	return &Client{
+		http: &http.Client{
+			Timeout:       timeout,
+			CheckRedirect: noRedirects,
+		},
+	}
+}
+
+// noRedirects возвращает получателю сам ответ с редиректом: переход мог бы увести запрос во внутреннюю сеть.
+func noRedirects(*http.Request, []*http.Request) error {
+	return http.ErrUseLastResponse
+}
+
+// blockedPrefixes - диапазоны, не попадающие под проверки net/netip, но тоже не публичные.
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("100.64.0.0/10"), // carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),  // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"), // сети для тестов производительности
+	netip.MustParsePrefix("64:ff9b::/96"),  // NAT64 может вести на внутренний IPv4
+}
+
+// isPublic сообщает, можно ли отправлять вебхук на адрес: не loopback, не частная сеть, не link-local.
+func isPublic(addr netip.Addr) bool {
+	addr = addr.Unmap()
+
+	if !addr.IsValid() ||
+		addr.IsUnspecified() ||
+		addr.IsLoopback() ||
+		addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() ||
+		addr.IsMulticast() {
+		return false
+	}
+
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Send отправляет JSON-тело POST-запросом с подписью и возвращает код ответа.
+// Успехом считается только ответ 2xx, для остальных кодов возвращается ошибка.
+func (c *Client) Send(ctx context.Context, req Request) (int, error) {
+	timestamp := time.Now().Unix()
+
+	if c.httpsOnly {
+		u, err := url.Parse(req.URL)
+		if err != nil {
+			return 0, err
+		}
+
+		if u.Scheme != "https" {
+			return 0, fmt.Errorf("%w: scheme %q", ErrForbiddenAddress, u.Scheme)
+		}
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, req.URL, bytes.NewReader(req.Body))
+	if err != nil {
+		return 0, err
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set(HeaderDelivery, strconv.FormatInt(req.DeliveryID, 10))
+	httpReq.Header.Set(HeaderEvent, req.Event)
+	httpReq.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	httpReq.Header.Set(HeaderSignature, Sign(req.Secret, timestamp, req.Body))
+
+	resp, err := c.http.Do(httpReq)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// Дочитываем тело, чтобы соединение вернулось в пул.
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// Sign возвращает подпись тела: HMAC-SHA256 от "<timestamp>.<body>" в hex с префиксом "sha256=".
+// Время входит в подпись, чтобы перехваченный запрос нельзя было повторить позже.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify проверяет подпись запроса на стороне получателя.
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+// Backoff возвращает паузу перед следующей попыткой после attempt неудачных: base, 2*base, 4*base...
+// но не больше max.
+func Backoff(attempt int, base, max time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= max {
+			return max
+		}
+	}
+
+	return min(delay, max)
+}
@@ -0,0 +1,168 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestClientSend(t *testing.T) {
+	const secret = "s3cret"
+	body := []byte(`{"id":42,"type":"created"}`)
+
+	var (
+		gotBody      []byte
+		gotHeaders   http.Header
+		verifiedSign bool
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = io.ReadAll(r.Body)
+		gotHeaders = r.Header.Clone()
+
+		timestamp, err := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+		if err == nil {
+			verifiedSign = Verify(secret, timestamp, gotBody, r.Header.Get(HeaderSignature))
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	code, err := NewLocalClient(time.Second).Send(context.Background(), Request{
+		URL:        srv.URL,
+		Secret:     secret,
+		DeliveryID: 7,
+		Event:      "created",
+		Body:       body,
+	})
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	if code != http.StatusNoContent {
+		t.Errorf("Send() code = %d, want %d", code, http.StatusNoContent)
+	}
+
+	if string(gotBody) != string(body) {
+		t.Errorf("receiver got body %q, want %q", gotBody, body)
+	}
+
+	if !verifiedSign {
+		t.Errorf("receiver could not verify signature %q", gotHeaders.Get(HeaderSignature))
+	}
+
+	if gotHeaders.Get(HeaderDelivery) != "7" || gotHeaders.Get(HeaderEvent) != "created" {
+		t.Errorf("unexpected headers: %v", gotHeaders)
+	}
+}
+
+func TestClientSendNon2xx(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	code, err := NewLocalClient(time.Second).Send(context.Background(), Request{URL: srv.URL, Secret: "x", Body: []byte("{}")})
+	if err == nil {
+		t.Fatal("Send() error = nil, want error for 503")
+	}
+
+	if code != http.StatusServiceUnavailable {
+		t.Errorf("Send() code = %d, want %d", code, http.StatusServiceUnavailable)
+	}
+}
+
+func TestVerifyRejectsTampering(t *testing.T) {
+	sig := Sign("secret", 100, []byte("body"))
+
+	if Verify("secret", 100, []byte("body!"), sig) {
+		t.Error("Verify() accepted modified body")
+	}
+
+	if Verify("secret", 101, []byte("body"), sig) {
+		t.Error("Verify() accepted modified timestamp")
+	}
+
+	if Verify("other", 100, []byte("body"), sig) {
+		t.Error("Verify() accepted wrong secret")
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	base, max := 30*time.Second, 5*time.Minute
+
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{4, 4 * time.Minute},
+		{5, 5 * time.Minute},
+		{40, 5 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := Backoff(tt.attempt, base, max); got != tt.want {
+			t.Errorf("Backoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestClientRejectsInternalAddresses(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	for _, url := range []string{srv.URL, "http://example.com/hook"} {
+		_, err := NewClient(time.Second).Send(context.Background(), Request{URL: url, Secret: "x", Body: []byte("{}")})
+		if !errors.Is(err, ErrForbiddenAddress) {
+			t.Errorf("Send(%q) error = %v, want ErrForbiddenAddress", url, err)
+		}
+	}
+}
+
+func TestClientDoesNotFollowRedirects(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://169.254.169.254/latest/meta-data/", http.StatusTemporaryRedirect)
+	}))
+	defer srv.Close()
+
+	code, err := NewLocalClient(time.Second).Send(context.Background(), Request{URL: srv.URL, Secret: "x", Body: []byte("{}")})
+	if err == nil || code != http.StatusTemporaryRedirect {
+		t.Errorf("Send() = %d, %v, want redirect status and error", code, err)
+	}
+}
+
+func TestIsPublic(t *testing.T) {
+	tests := map[string]bool{
+		"93.184.216.34":   true,
+		"2606:4700::1111": true,
+		"127.0.0.1":       false,
+		"10.1.2.3":        false,
+		"172.16.0.1":      false,
+		"192.168.1.1":     false,
+		"169.254.169.254": false,
+		"100.64.0.1":      false,
+		"0.0.0.0":         false,
+		"::1":             false,
+		"fe80::1":         false,
+		"fd00::1":         false,
+		"::ffff:10.0.0.1": false,
+	}
+
+	for addr, want := range tests {
+		if got := isPublic(netip.MustParseAddr(addr)); got != want {
+			t.Errorf("isPublic(%s) = %v, want %v", addr, got, want)
+		}
+	}
+}
@@ -4,10 +4,350 @@ import "time"
 
 type ContextKey string
 
+// Роли, от имени которых выполняются действия с бронями.
+const (
+	RoleAdmin    = "admin"
+	RoleCustomer = "customer"
+	// RoleSystem - действия, которые сервис выполняет сам, например отмена неоплаченной брони.
+	RoleSystem = "system"
+)
+
+// Типы событий в истории брони.
+const (
+	EventBookingCreated   = "created"
+	EventBookingCancelled = "cancelled"
+	EventBookingChanged   = "changed"
+)
+
+// Статусы брони.
+const (
+	StatusConfirmed       = "confirmed"
+	StatusCancelled       = "cancelled"
+	StatusCompleted       = "completed"
+	StatusNoShow          = "no_show"
+	StatusPendingApproval = "pending_approval"
+	StatusRejected        = "rejected"
+	// StatusPendingPayment - стол занят, но бронь ждёт оплаты депозита и отменится, если его не внести вовремя.
+	StatusPendingPayment = "pending_payment"
+)
+
+// Решения админа по брони, ожидающей подтверждения.
+const (
+	DecisionApproved = "approved"
+	DecisionRejected = "rejected"
+)
+
+// Статусы платежа за депозит.
+const (
+	PaymentPending  = "pending"
+	PaymentPaid     = "paid"
+	PaymentFailed   = "failed"
+	PaymentExpired  = "expired"
+	PaymentRefunded = "refunded"
+)
+
+// Payment - депозит за бронь. Amount - в минимальных единицах валюты (копейках).
+type Payment struct {
+	ID         int64     `json:"id"`
+	BookingID  int64     `json:"booking_id"`
+	ProviderID string    `json:"provider_id"`
+	Amount     int64     `json:"amount"`
+	Currency   string    `json:"currency"`
+	Status     string    `json:"status"`
+	PaymentURL string    `json:"payment_url"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// Поводы визита, которые гость может указать при бронировании.
+const (
+	OccasionBirthday    = "birthday"
+	OccasionAnniversary = "anniversary"
+	OccasionBusiness    = "business"
+	OccasionDate        = "date"
+	OccasionCelebration = "celebration"
+	OccasionOther       = "other"
+)
+
 type Booking struct {
-	UserID      int64
-	TableID     int16
-	BookingTime time.Time
+	ID      int64
+	UserID  int64
+	TableID int16
+	// TableIDs - все столы брони. Для брони одного стола содержит только TableID,
+	// для комбинации столов TableID - первый стол комбинации.
+	TableIDs      []int16
+	CombinationID int
+	BookingTime   time.Time
+	// EndsAt - когда гости освободят стол, зависит от размера компании и части дня.
+	EndsAt    time.Time
+	PartySize int16
+	Notes     string
+	Occasion  string
+	Allergens []string
+	// SeriesID - id повторяющейся брони, 0 - разовая бронь.
+	SeriesID int64
+	// Status - статус, с которым бронь сохраняется: confirmed, pending_approval или pending_payment.
+	Status string
+	// Deposit - депозит, который нужно внести, чтобы бронь не отменилась. Заполняется только для pending_payment.
+	Deposit *Payment `json:"-"`
+	// RequiredAttributes и PreferredAttributes - пожелания гостя к месту. Если стол не указан,
+	// он подбирается среди столов со всеми обязательными атрибутами с учётом желательных.
+	RequiredAttributes  []string `json:"-"`
+	PreferredAttributes []string `json:"-"`
+	// Attributes - атрибуты, общие для всех столов брони.
+	Attributes []string `json:",omitempty"`
+	// PromoCode - промокод, применённый к брони, PromoDescription - что он даёт гостю.
+	PromoCode        string `json:",omitempty"`
+	PromoDescription string `json:",omitempty"`
+	// PreOrder - блюда, заказанные заранее вместе с бронью.
+	PreOrder []PreOrderItem `json:",omitempty"`
+	// PreOrderChanged - уведомление о том, что гость изменил предзаказ существующей брони.
+	PreOrderChanged bool `json:",omitempty"`
+	// Occurrences - сколько дат серии забронировано, заполняется только в уведомлении о серии.
+	Occurrences int `json:",omitempty"`
+	// Decision, Reason и Email заполняются только в уведомлении клиенту о решении админа по брони.
+	Decision string `json:",omitempty"`
+	Reason   string `json:",omitempty"`
+	Email    string `json:",omitempty"`
+	// FeedbackURL заполняется только в просьбе к клиенту оставить отзыв о завершённом визите.
+	FeedbackURL string `json:",omitempty"`
+}
+
+// PromoCode - промокод или подарочный сертификат, который гость указывает при бронировании.
+// Код действует для визитов в [ValidFrom, ValidUntil) и, если Weekdays не пустой, только в эти дни недели.
+// MaxUses nil - без ограничения числа использований.
+type PromoCode struct {
+	ID          int64     `json:"id"`
+	Code        string    `json:"code"`
+	Description string    `json:"description"`
+	ValidFrom   time.Time `json:"valid_from"`
+	ValidUntil  time.Time `json:"valid_until"`
+	MaxUses     *int      `json:"max_uses,omitempty"`
+	UsedCount   int       `json:"used_count"`
+	Weekdays    []string  `json:"weekdays"`
+	IsActive    bool      `json:"is_active"`
+	CreatedBy   int64     `json:"created_by"`
+}
+
+// MenuCategory - раздел меню. Dishes заполнен только в меню для гостей.
+type MenuCategory struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Position - порядок раздела в меню, меньше - выше.
+	Position int    `json:"position"`
+	Dishes   []Dish `json:"dishes,omitempty"`
+}
+
+// Dish - блюдо меню. Price - в минимальных единицах валюты (копейках).
+// IsAvailable false - блюдо временно закончилось, но остаётся в меню.
+type Dish struct {
+	ID          int64    `json:"id"`
+	CategoryID  int64    `json:"category_id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Price       int64    `json:"price"`
+	Allergens   []string `json:"allergens"`
+	IsAvailable bool     `json:"is_available"`
+	Position    int      `json:"position"`
+}
+
+// PreOrderItem - блюдо в предзаказе. Name и Price берутся из меню на момент заказа,
+// чтобы изменение меню не меняло уже сделанный заказ.
+type PreOrderItem struct {
+	DishID   int64  `json:"dish_id"`
+	Name     string `json:"name"`
+	Quantity int    `json:"quantity"`
+	Price    int64  `json:"price"`
+}
+
+// SpecialEvent - событие с фиксированным временем начала, например винный ужин.
+// Места продаются поштучно, а не столами. Price - цена одного места в копейках.
+type SpecialEvent struct {
+	ID            int64     `json:"id"`
+	Title         string    `json:"title"`
+	Description   string    `json:"description"`
+	StartsAt      time.Time `json:"starts_at"`
+	EndsAt        time.Time `json:"ends_at"`
+	Capacity      int       `json:"capacity"`
+	SeatsReserved int       `json:"seats_reserved"`
+	SeatsLeft     int       `json:"seats_left"`
+	Price         int64     `json:"price"`
+	IsActive      bool      `json:"is_active"`
+	CreatedBy     int64     `json:"created_by"`
+}
+
+// EventReservation - места гостя на событии. Amount - стоимость всех мест на момент резервации.
+type EventReservation struct {
+	ID        int64     `json:"id"`
+	EventID   int64     `json:"event_id"`
+	UserID    int64     `json:"user_id"`
+	Seats     int       `json:"seats"`
+	Amount    int64     `json:"amount"`
+	IsActive  bool      `json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+	// Email, FirstName и LastName - контакты гостя, заполняются в списке гостей события.
+	Email     string `json:"email,omitempty"`
+	FirstName string `json:"first_name,omitempty"`
+	LastName  string `json:"last_name,omitempty"`
+}
+
+// Виды операций с баллами лояльности.
+const (
+	// LoyaltyVisit - начисление за завершённый визит.
+	LoyaltyVisit = "visit"
+	// LoyaltyNoShow - списание за неявку.
+	LoyaltyNoShow = "no_show"
+	// LoyaltyAdjustment - ручная корректировка админом.
+	LoyaltyAdjustment = "adjustment"
+)
+
+// LoyaltyTransaction - запись в журнале баллов лояльности. Points положительный при начислении
+// и отрицательный при списании.
+type LoyaltyTransaction struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	BookingID *int64    `json:"booking_id,omitempty"`
+	Points    int       `json:"points"`
+	Kind      string    `json:"kind"`
+	Reason    string    `json:"reason,omitempty"`
+	CreatedBy *int64    `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// LoyaltyAccount - баланс баллов пользователя и последние операции с ними.
+type LoyaltyAccount struct {
+	UserID       int64                `json:"user_id"`
+	Balance      int                  `json:"balance"`
+	Transactions []LoyaltyTransaction `json:"transactions"`
+}
+
+// TableCombination - заранее заданный набор столов, которые сдвигают для большой компании.
+type TableCombination struct {
+	ID       int     `json:"id"`
+	Name     string  `json:"name"`
+	TableIDs []int16 `json:"table_ids"`
+	Capacity int16   `json:"capacity"`
+}
+
+// Формы столов на плане зала.
+const (
+	ShapeSquare = "square"
+	ShapeRound  = "round"
+	ShapeRect   = "rect"
+)
+
+// Атрибуты места, которые гость может запросить при бронировании.
+const (
+	AttributeWindow     = "window"
+	AttributeTerrace    = "terrace"
+	AttributeBooth      = "booth"
+	AttributeQuiet      = "quiet"
+	AttributeWheelchair = "wheelchair_accessible"
+)
+
+// Table - стол ресторана, зал, в котором он стоит, и его место на плане зала.
+// Координаты и размеры задаются в условных единицах сетки плана, Rotation - в градусах.
+type Table struct {
+	ID       int16   `json:"id"`
+	Zone     string  `json:"zone"`
+	X        float32 `json:"x"`
+	Y        float32 `json:"y"`
+	Width    float32 `json:"width"`
+	Height   float32 `json:"height"`
+	Rotation int16   `json:"rotation"`
+	Shape    string  `json:"shape"`
+	Seats    int16   `json:"seats"`
+	// Attributes - особенности места: у окна, на террасе, доступно для колясок и т.п.
+	Attributes []string `json:"attributes"`
+}
+
+// Состояния стола на плане зала.
+const (
+	FloorStatusFree       = "free"
+	FloorStatusBookedSoon = "booked_soon"
+	FloorStatusSeated     = "seated"
+	FloorStatusBlocked    = "blocked"
+)
+
+// FloorPlanTable - стол на плане зала с текущим состоянием. Booking - бронь, из-за которой стол
+// занят или скоро будет занят.
+type FloorPlanTable struct {
+	Table
+	Status  string       `json:"status"`
+	Booking *BookingInfo `json:"booking,omitempty"`
+}
+
+// TableBlock - период, когда столы нельзя бронировать: ремонт, закрытое мероприятие, аренда зала.
+// Если блок создан на зал, Zone - его название, а TableIDs - столы зала на момент создания.
+type TableBlock struct {
+	ID        int64     `json:"id"`
+	TableIDs  []int16   `json:"table_ids"`
+	Zone      string    `json:"zone,omitempty"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	Reason    string    `json:"reason"`
+	CreatedBy int64     `json:"created_by"`
+}
+
+// ZoneSchedule - период, когда зал открыт, например летняя терраса с мая по сентябрь по вечерам.
+// Если у зала есть расписания, его столы можно бронировать, только когда визит целиком
+// попадает в одно из них. Даты - в формате YYYY-MM-DD, время - HH:MM по времени ресторана.
+type ZoneSchedule struct {
+	ID        int64  `json:"id"`
+	Zone      string `json:"zone"`
+	StartsOn  string `json:"starts_on"`
+	EndsOn    string `json:"ends_on"`
+	OpensAt   string `json:"opens_at"`
+	ClosesAt  string `json:"closes_at"`
+	CreatedBy int64  `json:"created_by"`
+}
+
+// Состояния стола в ответе о доступности.
+const (
+	AvailabilityFree    = "free"
+	AvailabilityBooked  = "booked"
+	AvailabilityBlocked = "blocked"
+	// AvailabilityPacingLimit - стол свободен, но кухня уже не примет ещё гостей в этот интервал.
+	AvailabilityPacingLimit = "pacing_limit"
+)
+
+// TableAvailability - можно ли забронировать стол на выбранное время.
+type TableAvailability struct {
+	TableID int16  `json:"table_id"`
+	Zone    string `json:"zone"`
+	Status  string `json:"status"`
+}
+
+// BookingAlternatives - варианты, которые предлагаются гостю, если выбранные столы заняты.
+type BookingAlternatives struct {
+	// Times - ближайшее время, когда свободны те же столы.
+	Times []time.Time `json:"times"`
+	// Tables - другие столы, свободные в запрошенное время и вмещающие компанию.
+	Tables []Table `json:"tables"`
+}
+
+// BookingSeries - повторяющаяся бронь одного стола раз в IntervalWeeks недель.
+type BookingSeries struct {
+	ID            int64     `json:"id"`
+	UserID        int64     `json:"user_id"`
+	TableID       int16     `json:"table_id"`
+	StartsAt      time.Time `json:"starts_at"`
+	IntervalWeeks int       `json:"interval_weeks"`
+	Occurrences   int       `json:"occurrences"`
+	IsActive      bool      `json:"is_active"`
+}
+
+// SeriesConflict - дата серии, которую не удалось забронировать.
+type SeriesConflict struct {
+	BookingTime time.Time `json:"booking_time"`
+	Reason      string    `json:"reason"`
+}
+
+// SeriesResult - итог создания повторяющейся брони.
+type SeriesResult struct {
+	SeriesID  int64            `json:"series_id"`
+	Booked    []time.Time      `json:"booked"`
+	Conflicts []SeriesConflict `json:"conflicts"`
 }
 
 type User struct {
@@ -17,10 +357,198 @@ type User struct {
 	Last_name  string
 }
 
+// Actor описывает пользователя, выполнившего действие с бронью.
+type Actor struct {
+	ID   int64
+	Role string
+}
+
 type BookingInfo struct {
-	BookingTime time.Time `json:"booking_time"`
+	ID            int64      `json:"id"`
+	UserID        int64      `json:"user_id"`
+	BookingTime   time.Time  `json:"booking_time"`
+	EndsAt        time.Time  `json:"ends_at"`
+	TableID       int16      `json:"table_id"`
+	TableIDs      []int16    `json:"table_ids"`
+	CombinationID *int       `json:"combination_id,omitempty"`
+	PartySize     int16      `json:"party_size"`
+	Status        string     `json:"status"`
+	Notes         string     `json:"notes"`
+	Occasion      string     `json:"occasion"`
+	Allergens     []string   `json:"allergens"`
+	SeriesID      *int64     `json:"series_id,omitempty"`
+	CheckedInAt   *time.Time `json:"checked_in_at,omitempty"`
+	PromoCode     string     `json:"promo_code,omitempty"`
+	Email         string     `json:"email"`
+	FirstName     string     `json:"first_name"`
+	LastName      string     `json:"last_name"`
+	// Profile - профиль гостя, заполняется только в списке броней для админа.
+	Profile *CustomerProfile `json:"profile,omitempty"`
+}
+
+// TagBlocked - тег профиля гостя, с которым он не может бронировать столы и места на событиях.
+// Остальные теги - просто пометки для хостес.
+const TagBlocked = "blocked"
+
+// CustomerProfile - профиль гостя для хостес. Visits, NoShows, Cancellations, LastVisit, FavouriteTable
+// и Allergens считаются по истории броней, Tags (например "vip" или TagBlocked) и Notes ведут админы.
+type CustomerProfile struct {
+	UserID        int64      `json:"user_id"`
+	Visits        int        `json:"visits"`
+	NoShows       int        `json:"no_shows"`
+	Cancellations int        `json:"cancellations"`
+	LastVisit     *time.Time `json:"last_visit,omitempty"`
+	// FavouriteTable - стол, за которым гость чаще всего сидел на завершённых визитах.
+	FavouriteTable *int16   `json:"favourite_table,omitempty"`
+	Allergens      []string `json:"allergens"`
+	Tags           []string `json:"tags"`
+	Notes          string   `json:"notes"`
+}
+
+// BookingFilter - фильтр списка броней. Mode - "all", "active" или "pending" (ждут подтверждения админа),
+// From и To ограничивают время брони полуинтервалом [From, To), nil - без ограничения.
+type BookingFilter struct {
+	Mode string
+	From *time.Time
+	To   *time.Time
+}
+
+// BookingSnapshot - состояние брони до или после события.
+type BookingSnapshot struct {
+	UserID      int64     `json:"user_id"`
 	TableID     int16     `json:"table_id"`
-	Email       string    `json:"email"`
-	FirstName   string    `json:"first_name"`
-	LastName    string    `json:"last_name"`
+	TableIDs    []int16   `json:"table_ids,omitempty"`
+	BookingTime time.Time `json:"booking_time"`
+	PartySize   int16     `json:"party_size"`
+	Status      string    `json:"status"`
+	IsActive    bool      `json:"is_active"`
+	CheckedIn   bool      `json:"checked_in,omitempty"`
+}
+
+// BookingEvent - запись в истории изменений брони.
+type BookingEvent struct {
+	ID        int64            `json:"id"`
+	BookingID int64            `json:"booking_id"`
+	EventType string           `json:"event_type"`
+	ActorID   int64            `json:"actor_id"`
+	ActorRole string           `json:"actor_role"`
+	Before    *BookingSnapshot `json:"before,omitempty"`
+	After     *BookingSnapshot `json:"after,omitempty"`
+	CreatedAt time.Time        `json:"created_at"`
+	TxID      int64            `json:"-"` // транзакция, записавшая событие
+}
+
+// EventCursor - позиция в ленте событий броней. События упорядочены по транзакции и id,
+// потому что id выдаётся при вставке и транзакции могут закоммититься в другом порядке.
+type EventCursor struct {
+	TxID int64
+	ID   int64
+}
+
+// Before сообщает, идёт ли событие e в ленте после позиции c.
+func (c EventCursor) Before(e BookingEvent) bool {
+	return e.TxID > c.TxID || e.TxID == c.TxID && e.ID > c.ID
+}
+
+// Cursor возвращает позицию события в ленте.
+func (e BookingEvent) Cursor() EventCursor {
+	return EventCursor{TxID: e.TxID, ID: e.ID}
+}
+
+// CoversStat - количество броней и гостей за период (день или час).
+type CoversStat struct {
+	Period   time.Time `json:"period"`
+	Bookings int       `json:"bookings"`
+	Covers   int       `json:"covers"`
+}
+
+// UtilisationStat - загрузка столов за день в процентах от доступного времени.
+type UtilisationStat struct {
+	Date           time.Time `json:"date"`
+	BookedHours    float64   `json:"booked_hours"`
+	AvailableHours float64   `json:"available_hours"`
+	Utilisation    float64   `json:"utilisation"`
+}
+
+// BookingRates - доли отмен и неявок и среднее время между созданием брони и визитом.
+type BookingRates struct {
+	Total            int     `json:"total"`
+	Cancelled        int     `json:"cancelled"`
+	NoShow           int     `json:"no_show"`
+	Completed        int     `json:"completed"`
+	CancellationRate float64 `json:"cancellation_rate"`
+	NoShowRate       float64 `json:"no_show_rate"`
+	AvgLeadTimeHours float64 `json:"avg_lead_time_hours"`
+}
+
+// TableStat - статистика броней по столу.
+type TableStat struct {
+	TableID  int16 `json:"table_id"`
+	Bookings int   `json:"bookings"`
+	Covers   int   `json:"covers"`
+}
+
+// Review - отзыв гостя о визите по брони. Rating - от 1 до 5.
+type Review struct {
+	ID        int64     `json:"id"`
+	BookingID int64     `json:"booking_id"`
+	Rating    int       `json:"rating"`
+	Comment   string    `json:"comment"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RatingStat - количество отзывов и средняя оценка. Date заполнена в разбивке по дням, TableID - по столам.
+type RatingStat struct {
+	Date          *time.Time `json:"date,omitempty"`
+	TableID       int16      `json:"table_id,omitempty"`
+	Reviews       int        `json:"reviews"`
+	AverageRating float64    `json:"average_rating"`
+}
+
+// RatingsReport - оценки гостей за период: в целом, по дням визита и по столам.
+type RatingsReport struct {
+	Overall RatingStat   `json:"overall"`
+	ByDay   []RatingStat `json:"by_day"`
+	ByTable []RatingStat `json:"by_table"`
+}
+
+// Статусы доставки события подписчику вебхука.
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed"
+)
+
+// Webhook - подписка внешней системы на события броней. Secret отдаётся только при создании.
+type Webhook struct {
+	ID           int64     `json:"id"`
+	URL          string    `json:"url"`
+	EventTypes   []string  `json:"event_types"`
+	Secret       string    `json:"secret,omitempty"`
+	IsActive     bool      `json:"is_active"`
+	FailureCount int       `json:"failure_count"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// WebhookDelivery - событие брони, которое нужно доставить подписчику.
+type WebhookDelivery struct {
+	ID        int64
+	WebhookID int64
+	URL       string
+	Secret    string
+	Attempts  int
+	Event     BookingEvent
+}
+
+// WebhookAttempt - одна попытка доставки события подписчику.
+type WebhookAttempt struct {
+	ID          int64     `json:"id"`
+	DeliveryID  int64     `json:"delivery_id"`
+	EventID     int64     `json:"event_id"`
+	EventType   string    `json:"event_type"`
+	Attempt     int       `json:"attempt"`
+	StatusCode  int       `json:"status_code"`
+	Error       string    `json:"error,omitempty"`
+	DurationMs  int       `json:"duration_ms"`
+	AttemptedAt time.Time `json:"attempted_at"`
 }
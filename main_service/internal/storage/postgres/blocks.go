@@ -0,0 +1,182 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+	"time"
+)
+
+// SaveBlock сохраняет блокировку столов и возвращает её с присвоенным id.
+// Если указан зал, блокируются все его столы на момент создания.
+func (r *PostgresRepo) SaveBlock(ctx context.Context, block models.TableBlock) (models.TableBlock, error) {
+	const op = "storage.postgres.SaveBlock"
+
+	if block.Zone != "" {
+		err := r.pool.QueryRow(
+			ctx,
+			`SELECT COALESCE(array_agg(id ORDER BY id), '{}') FROM restaurant_tables WHERE zone = $1`,
+			block.Zone,
+		).Scan(&block.TableIDs)
+		if err != nil {
+			return models.TableBlock{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if len(block.TableIDs) == 0 {
+			return models.TableBlock{}, fmt.Errorf("%s: %w", op, storage.ErrZoneNotFound)
+		}
+	}
+
+	err := r.pool.QueryRow(
+		ctx,
+		`INSERT INTO table_blocks (table_ids, zone, starts_at, ends_at, reason, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`,
+		block.TableIDs,
+		block.Zone,
+		block.StartsAt,
+		block.EndsAt,
+		block.Reason,
+		block.CreatedBy,
+	).Scan(&block.ID)
+	if err != nil {
+		return models.TableBlock{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return block, nil
+}
+
+// GetBlocks возвращает действующие блокировки, которые ещё не закончились к from.
+func (r *PostgresRepo) GetBlocks(ctx context.Context, from time.Time) ([]models.TableBlock, error) {
+	const op = "storage.postgres.GetBlocks"
+
+	rows, err := r.pool.Query(
+		ctx,
+		`SELECT id, table_ids, zone, starts_at, ends_at, reason, created_by
+		FROM table_blocks
+		WHERE is_active = TRUE AND ends_at > $1
+		ORDER BY starts_at, id`,
+		from,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	blocks := []models.TableBlock{}
+	for rows.Next() {
+		var b models.TableBlock
+		if err := rows.Scan(&b.ID, &b.TableIDs, &b.Zone, &b.StartsAt, &b.EndsAt, &b.Reason, &b.CreatedBy); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		blocks = append(blocks, b)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return blocks, nil
+}
+
+// DeactivateBlock снимает блокировку столов.
+func (r *PostgresRepo) DeactivateBlock(ctx context.Context, id int64) error {
+	const op = "storage.postgres.DeactivateBlock"
+
+	cmdTag, err := r.pool.Exec(
+		ctx,
+		`UPDATE table_blocks SET is_active = FALSE WHERE id = $1 AND is_active = TRUE`,
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrBlockNotFound)
+	}
+
+	return nil
+}
+
+// IsTableBlocked проверяет, пересекается ли хотя бы один из столов с действующей блокировкой в промежутке [from, to).
+func (r *PostgresRepo) IsTableBlocked(ctx context.Context, tableIDs []int16, from, to time.Time) (bool, error) {
+	const op = "storage.postgres.IsTableBlocked"
+
+	var blocked bool
+	err := r.pool.QueryRow(
+		ctx,
+		`SELECT EXISTS(
+			SELECT 1
+			FROM table_blocks
+			WHERE is_active = TRUE AND table_ids && $1 AND starts_at < $3 AND ends_at > $2
+		)`,
+		tableIDs,
+		from,
+		to,
+	).Scan(&blocked)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return blocked, nil
+}
+
+// GetBlockedTables возвращает столы, заблокированные хотя бы на часть промежутка [from, to).
+func (r *PostgresRepo) GetBlockedTables(ctx context.Context, from, to time.Time) ([]int16, error) {
+	const op = "storage.postgres.GetBlockedTables"
+
+	var tableIDs []int16
+	err := r.pool.QueryRow(
+		ctx,
+		`SELECT COALESCE(array_agg(DISTINCT t.table_id), '{}')
+		FROM table_blocks b, unnest(b.table_ids) AS t(table_id)
+		WHERE b.is_active = TRUE AND b.starts_at < $2 AND b.ends_at > $1`,
+		from,
+		to,
+	).Scan(&tableIDs)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tableIDs, nil
+}
+
+// GetBlockConflicts возвращает активные брони, которые пересекаются с блокировкой.
+func (r *PostgresRepo) GetBlockConflicts(ctx context.Context, block models.TableBlock) ([]models.BookingInfo, error) {
+	const op = "storage.postgres.GetBlockConflicts"
+
+	rows, err := r.pool.Query(
+		ctx,
+		`SELECT `+bookingInfoColumns+`
+		FROM bookings b
+		JOIN users u ON u.id = b.user_id
+		WHERE b.is_active = TRUE
+			AND b.table_ids && $1
+			AND b.booking_time < $3
+			AND b.ends_at > $2
+		ORDER BY b.booking_time`,
+		block.TableIDs,
+		block.StartsAt,
+		block.EndsAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	bookings := []models.BookingInfo{}
+	for rows.Next() {
+		b, err := scanBookingInfo(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		bookings = append(bookings, b)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return bookings, nil
+}
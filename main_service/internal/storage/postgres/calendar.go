@@ -0,0 +1,49 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"main_service/internal/storage"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// SaveCalendarToken сохраняет хэш токена календарной ленты пользователя, заменяя предыдущий.
+func (r *PostgresRepo) SaveCalendarToken(ctx context.Context, userID int64, tokenHash string) error {
+	const op = "storage.postgres.SaveCalendarToken"
+
+	_, err := r.pool.Exec(
+		ctx,
+		`INSERT INTO calendar_tokens (user_id, token_hash) VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET token_hash = EXCLUDED.token_hash, created_at = NOW()`,
+		userID,
+		tokenHash,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// UserByCalendarToken возвращает id владельца токена календарной ленты.
+func (r *PostgresRepo) UserByCalendarToken(ctx context.Context, tokenHash string) (int64, error) {
+	const op = "storage.postgres.UserByCalendarToken"
+
+	var userID int64
+	err := r.pool.QueryRow(
+		ctx,
+		`SELECT user_id FROM calendar_tokens WHERE token_hash = $1`,
+		tokenHash,
+	).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, fmt.Errorf("%s: %w", op, storage.ErrTokenNotFound)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return userID, nil
+}
@@ -0,0 +1,160 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+)
+
+// GetCustomerProfiles возвращает профили гостей userIDs по их id. Статистика визитов считается
+// по истории броней, теги и заметки берутся из customer_profiles. Гостей, которых нет в users, в результате нет.
+func (r *PostgresRepo) GetCustomerProfiles(ctx context.Context, userIDs []int64) (map[int64]models.CustomerProfile, error) {
+	const op = "storage.postgres.GetCustomerProfiles"
+
+	profiles := make(map[int64]models.CustomerProfile, len(userIDs))
+	if len(userIDs) == 0 {
+		return profiles, nil
+	}
+
+	rows, err := r.pool.Query(
+		ctx,
+		`WITH stats AS (
+			SELECT user_id,
+				COUNT(*) FILTER (WHERE status = $2) AS visits,
+				COUNT(*) FILTER (WHERE status = $3) AS no_shows,
+				COUNT(*) FILTER (WHERE status = $4) AS cancellations,
+				MAX(booking_time) FILTER (WHERE status = $2) AS last_visit
+			FROM bookings
+			WHERE user_id = ANY($1)
+			GROUP BY user_id
+		), favourite AS (
+			SELECT DISTINCT ON (b.user_id) b.user_id, t.table_id
+			FROM bookings b, unnest(b.table_ids) AS t(table_id)
+			WHERE b.user_id = ANY($1) AND b.status = $2
+			GROUP BY b.user_id, t.table_id
+			ORDER BY b.user_id, COUNT(*) DESC, t.table_id
+		), allergens AS (
+			SELECT b.user_id, array_agg(DISTINCT a.allergen ORDER BY a.allergen) AS allergens
+			FROM bookings b, unnest(b.allergens) AS a(allergen)
+			WHERE b.user_id = ANY($1)
+			GROUP BY b.user_id
+		)
+		SELECT u.id,
+			COALESCE(s.visits, 0),
+			COALESCE(s.no_shows, 0),
+			COALESCE(s.cancellations, 0),
+			s.last_visit,
+			f.table_id,
+			COALESCE(a.allergens, '{}'),
+			COALESCE(p.tags, '{}'),
+			COALESCE(p.notes, '')
+		FROM users u
+		LEFT JOIN stats s ON s.user_id = u.id
+		LEFT JOIN favourite f ON f.user_id = u.id
+		LEFT JOIN allergens a ON a.user_id = u.id
+		LEFT JOIN customer_profiles p ON p.user_id = u.id
+		WHERE u.id = ANY($1)`,
+		userIDs,
+		models.StatusCompleted,
+		models.StatusNoShow,
+		models.StatusCancelled,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var p models.CustomerProfile
+		err := rows.Scan(
+			&p.UserID,
+			&p.Visits,
+			&p.NoShows,
+			&p.Cancellations,
+			&p.LastVisit,
+			&p.FavouriteTable,
+			&p.Allergens,
+			&p.Tags,
+			&p.Notes,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		profiles[p.UserID] = p
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return profiles, nil
+}
+
+// GetCustomerProfile возвращает профиль гостя. Если пользователя нет, возвращается storage.ErrUserNotFound.
+func (r *PostgresRepo) GetCustomerProfile(ctx context.Context, userID int64) (models.CustomerProfile, error) {
+	const op = "storage.postgres.GetCustomerProfile"
+
+	profiles, err := r.GetCustomerProfiles(ctx, []int64{userID})
+	if err != nil {
+		return models.CustomerProfile{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	profile, ok := profiles[userID]
+	if !ok {
+		return models.CustomerProfile{}, fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+	}
+
+	return profile, nil
+}
+
+// HasCustomerTag сообщает, отметили ли админы гостя тегом tag.
+func (r *PostgresRepo) HasCustomerTag(ctx context.Context, userID int64, tag string) (bool, error) {
+	const op = "storage.postgres.HasCustomerTag"
+
+	var tagged bool
+	err := r.pool.QueryRow(
+		ctx,
+		`SELECT EXISTS(SELECT 1 FROM customer_profiles WHERE user_id = $1 AND $2 = ANY(tags))`,
+		userID,
+		tag,
+	).Scan(&tagged)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tagged, nil
+}
+
+// SaveCustomerProfile сохраняет теги и заметки админов о госте, заменяя прежние. Статистика визитов из profile не сохраняется.
+// Если пользователя нет, возвращается storage.ErrUserNotFound.
+func (r *PostgresRepo) SaveCustomerProfile(ctx context.Context, profile models.CustomerProfile, updatedBy int64) error {
+	const op = "storage.postgres.SaveCustomerProfile"
+
+	var exists bool
+	err := r.pool.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM users WHERE id = $1)`, profile.UserID).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !exists {
+		return fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+	}
+
+	_, err = r.pool.Exec(
+		ctx,
+		`INSERT INTO customer_profiles (user_id, tags, notes, updated_by)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id) DO UPDATE
+		SET tags = EXCLUDED.tags, notes = EXCLUDED.notes, updated_by = EXCLUDED.updated_by, updated_at = NOW()`,
+		profile.UserID,
+		tags(profile.Tags),
+		profile.Notes,
+		updatedBy,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
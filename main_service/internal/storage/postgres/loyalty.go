@@ -0,0 +1,144 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// CloseBooking переводит подтверждённую бронь в итоговый статус визита и в той же транзакции записывает
+// в журнал лояльности начисление или списание баллов entry. Если entry nil, баллы не меняются.
+// Если бронь не подтверждена, возвращается storage.ErrUnexpectedStatus.
+func (r *PostgresRepo) CloseBooking(ctx context.Context, bookingID int64, status string, entry *models.LoyaltyTransaction, actor models.Actor) error {
+	const op = "storage.postgres.CloseBooking"
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	booking, err := updateBookingStatus(ctx, tx, bookingID, models.StatusConfirmed, status, actor)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if entry != nil {
+		entry.UserID = booking.UserID
+		entry.BookingID = &booking.ID
+
+		// Повторная запись по той же брони не создаётся благодаря уникальному индексу.
+		_, err = tx.Exec(
+			ctx,
+			`INSERT INTO loyalty_transactions (user_id, booking_id, points, kind, reason, created_by)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT DO NOTHING`,
+			entry.UserID,
+			entry.BookingID,
+			entry.Points,
+			entry.Kind,
+			entry.Reason,
+			entry.CreatedBy,
+		)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// SaveLoyaltyAdjustment записывает в журнал ручную корректировку баланса и возвращает запись.
+// Если пользователя нет, возвращается storage.ErrUserNotFound.
+func (r *PostgresRepo) SaveLoyaltyAdjustment(ctx context.Context, entry models.LoyaltyTransaction) (models.LoyaltyTransaction, error) {
+	const op = "storage.postgres.SaveLoyaltyAdjustment"
+
+	var exists bool
+	err := r.pool.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM users WHERE id = $1)`, entry.UserID).Scan(&exists)
+	if err != nil {
+		return models.LoyaltyTransaction{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !exists {
+		return models.LoyaltyTransaction{}, fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+	}
+
+	err = r.pool.QueryRow(
+		ctx,
+		`INSERT INTO loyalty_transactions (user_id, points, kind, reason, created_by)
+		VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at`,
+		entry.UserID,
+		entry.Points,
+		models.LoyaltyAdjustment,
+		entry.Reason,
+		entry.CreatedBy,
+	).Scan(&entry.ID, &entry.CreatedAt)
+	if err != nil {
+		return models.LoyaltyTransaction{}, fmt.Errorf("%s: %w", op, err)
+	}
+	entry.Kind = models.LoyaltyAdjustment
+
+	return entry, nil
+}
+
+// GetLoyaltyAccount возвращает баланс баллов пользователя и не больше limit последних операций, новые первыми.
+func (r *PostgresRepo) GetLoyaltyAccount(ctx context.Context, userID int64, limit int) (models.LoyaltyAccount, error) {
+	const op = "storage.postgres.GetLoyaltyAccount"
+
+	account := models.LoyaltyAccount{
+		UserID:       userID,
+		Transactions: []models.LoyaltyTransaction{},
+	}
+
+	// Баланс и операции читаются из одного снимка, чтобы сумма сходилась с журналом.
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return models.LoyaltyAccount{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(
+		ctx,
+		`SELECT COALESCE(SUM(points), 0) FROM loyalty_transactions WHERE user_id = $1`,
+		userID,
+	).Scan(&account.Balance)
+	if err != nil {
+		return models.LoyaltyAccount{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := tx.Query(
+		ctx,
+		`SELECT id, user_id, booking_id, points, kind, reason, created_by, created_at
+		FROM loyalty_transactions
+		WHERE user_id = $1
+		ORDER BY id DESC
+		LIMIT $2`,
+		userID,
+		limit,
+	)
+	if err != nil {
+		return models.LoyaltyAccount{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var t models.LoyaltyTransaction
+		if err := rows.Scan(&t.ID, &t.UserID, &t.BookingID, &t.Points, &t.Kind, &t.Reason, &t.CreatedBy, &t.CreatedAt); err != nil {
+			return models.LoyaltyAccount{}, fmt.Errorf("%s: %w", op, err)
+		}
+		account.Transactions = append(account.Transactions, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return models.LoyaltyAccount{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return account, nil
+}
@@ -0,0 +1,256 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// SaveCategory сохраняет раздел меню и возвращает его с присвоенным id.
+func (r *PostgresRepo) SaveCategory(ctx context.Context, category models.MenuCategory) (models.MenuCategory, error) {
+	const op = "storage.postgres.SaveCategory"
+
+	err := r.pool.QueryRow(
+		ctx,
+		`INSERT INTO menu_categories (name, description, position) VALUES ($1, $2, $3) RETURNING id`,
+		category.Name,
+		category.Description,
+		category.Position,
+	).Scan(&category.ID)
+	if err != nil {
+		return models.MenuCategory{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return category, nil
+}
+
+// UpdateCategory меняет название, описание и порядок раздела меню.
+func (r *PostgresRepo) UpdateCategory(ctx context.Context, category models.MenuCategory) error {
+	const op = "storage.postgres.UpdateCategory"
+
+	cmdTag, err := r.pool.Exec(
+		ctx,
+		`UPDATE menu_categories SET name = $2, description = $3, position = $4 WHERE id = $1 AND is_active = TRUE`,
+		category.ID,
+		category.Name,
+		category.Description,
+		category.Position,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrCategoryNotFound)
+	}
+
+	return nil
+}
+
+// DeactivateCategory убирает раздел из меню. Раздел, в котором остались блюда, убрать нельзя.
+func (r *PostgresRepo) DeactivateCategory(ctx context.Context, id int64) error {
+	const op = "storage.postgres.DeactivateCategory"
+
+	var hasDishes bool
+	err := r.pool.QueryRow(
+		ctx,
+		`SELECT EXISTS(SELECT 1 FROM dishes WHERE category_id = $1 AND is_active = TRUE)`,
+		id,
+	).Scan(&hasDishes)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if hasDishes {
+		return fmt.Errorf("%s: %w", op, storage.ErrCategoryNotEmpty)
+	}
+
+	cmdTag, err := r.pool.Exec(ctx, `UPDATE menu_categories SET is_active = FALSE WHERE id = $1 AND is_active = TRUE`, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrCategoryNotFound)
+	}
+
+	return nil
+}
+
+// SaveDish сохраняет блюдо и возвращает его с присвоенным id. Раздел блюда должен быть в меню.
+func (r *PostgresRepo) SaveDish(ctx context.Context, dish models.Dish) (models.Dish, error) {
+	const op = "storage.postgres.SaveDish"
+
+	err := r.pool.QueryRow(
+		ctx,
+		`INSERT INTO dishes (category_id, name, description, price, allergens, is_available, position)
+		SELECT $1, $2, $3, $4, $5, $6, $7
+		WHERE EXISTS(SELECT 1 FROM menu_categories WHERE id = $1 AND is_active = TRUE)
+		RETURNING id`,
+		dish.CategoryID,
+		dish.Name,
+		dish.Description,
+		dish.Price,
+		tags(dish.Allergens),
+		dish.IsAvailable,
+		dish.Position,
+	).Scan(&dish.ID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Dish{}, fmt.Errorf("%s: %w", op, storage.ErrCategoryNotFound)
+		}
+
+		return models.Dish{}, fmt.Errorf("%s: %w", op, err)
+	}
+	dish.Allergens = tags(dish.Allergens)
+
+	return dish, nil
+}
+
+// UpdateDish меняет блюдо целиком, в том числе его раздел и доступность.
+func (r *PostgresRepo) UpdateDish(ctx context.Context, dish models.Dish) error {
+	const op = "storage.postgres.UpdateDish"
+
+	var categoryExists bool
+	err := r.pool.QueryRow(
+		ctx,
+		`SELECT EXISTS(SELECT 1 FROM menu_categories WHERE id = $1 AND is_active = TRUE)`,
+		dish.CategoryID,
+	).Scan(&categoryExists)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !categoryExists {
+		return fmt.Errorf("%s: %w", op, storage.ErrCategoryNotFound)
+	}
+
+	cmdTag, err := r.pool.Exec(
+		ctx,
+		`UPDATE dishes
+		SET category_id = $2, name = $3, description = $4, price = $5, allergens = $6, is_available = $7, position = $8
+		WHERE id = $1 AND is_active = TRUE`,
+		dish.ID,
+		dish.CategoryID,
+		dish.Name,
+		dish.Description,
+		dish.Price,
+		tags(dish.Allergens),
+		dish.IsAvailable,
+		dish.Position,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrDishNotFound)
+	}
+
+	return nil
+}
+
+// DeactivateDish убирает блюдо из меню.
+func (r *PostgresRepo) DeactivateDish(ctx context.Context, id int64) error {
+	const op = "storage.postgres.DeactivateDish"
+
+	cmdTag, err := r.pool.Exec(ctx, `UPDATE dishes SET is_active = FALSE WHERE id = $1 AND is_active = TRUE`, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrDishNotFound)
+	}
+
+	return nil
+}
+
+// GetMenu возвращает разделы меню с их блюдами в порядке показа.
+func (r *PostgresRepo) GetMenu(ctx context.Context) ([]models.MenuCategory, error) {
+	const op = "storage.postgres.GetMenu"
+
+	rows, err := r.pool.Query(
+		ctx,
+		`SELECT id, name, description, position
+		FROM menu_categories
+		WHERE is_active = TRUE
+		ORDER BY position, id`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	menu := []models.MenuCategory{}
+	index := make(map[int64]int)
+	for rows.Next() {
+		var c models.MenuCategory
+		if err := rows.Scan(&c.ID, &c.Name, &c.Description, &c.Position); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		c.Dishes = []models.Dish{}
+		index[c.ID] = len(menu)
+		menu = append(menu, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	dishes, err := r.getDishes(ctx, `d.is_active = TRUE`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, d := range dishes {
+		if i, ok := index[d.CategoryID]; ok {
+			menu[i].Dishes = append(menu[i].Dishes, d)
+		}
+	}
+
+	return menu, nil
+}
+
+// GetDishes возвращает блюда меню с id из ids. Блюда, убранные из меню, не возвращаются.
+func (r *PostgresRepo) GetDishes(ctx context.Context, ids []int64) ([]models.Dish, error) {
+	const op = "storage.postgres.GetDishes"
+
+	dishes, err := r.getDishes(ctx, `d.is_active = TRUE AND d.id = ANY($1)`, ids)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return dishes, nil
+}
+
+// getDishes возвращает блюда, подходящие под условие where по таблице dishes d, в порядке показа.
+func (r *PostgresRepo) getDishes(ctx context.Context, where string, args ...any) ([]models.Dish, error) {
+	rows, err := r.pool.Query(
+		ctx,
+		`SELECT d.id, d.category_id, d.name, d.description, d.price, d.allergens, d.is_available, d.position
+		FROM dishes d
+		WHERE `+where+`
+		ORDER BY d.position, d.id`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	dishes := []models.Dish{}
+	for rows.Next() {
+		var d models.Dish
+		if err := rows.Scan(&d.ID, &d.CategoryID, &d.Name, &d.Description, &d.Price, &d.Allergens, &d.IsAvailable, &d.Position); err != nil {
+			return nil, err
+		}
+		dishes = append(dishes, d)
+	}
+
+	return dishes, rows.Err()
+}
@@ -0,0 +1,144 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const paymentColumns = `id, booking_id, provider_id, amount, currency, status, payment_url, expires_at`
+
+// SavePayment сохраняет платёж за депозит брони и возвращает его id.
+func (r *PostgresRepo) SavePayment(ctx context.Context, payment models.Payment) (int64, error) {
+	const op = "storage.postgres.SavePayment"
+
+	var id int64
+	err := r.pool.QueryRow(
+		ctx,
+		`INSERT INTO payments (booking_id, provider_id, amount, currency, status, payment_url, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`,
+		payment.BookingID,
+		payment.ProviderID,
+		payment.Amount,
+		payment.Currency,
+		payment.Status,
+		payment.PaymentURL,
+		payment.ExpiresAt,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// GetPaymentByProviderID возвращает платёж по id, который ему присвоил платёжный провайдер.
+func (r *PostgresRepo) GetPaymentByProviderID(ctx context.Context, providerID string) (models.Payment, error) {
+	const op = "storage.postgres.GetPaymentByProviderID"
+
+	p, err := scanPayment(r.pool.QueryRow(
+		ctx,
+		`SELECT `+paymentColumns+` FROM payments WHERE provider_id = $1`,
+		providerID,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Payment{}, fmt.Errorf("%s: %w", op, storage.ErrPaymentNotFound)
+		}
+
+		return models.Payment{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return p, nil
+}
+
+// GetBookingPayment возвращает последний платёж за депозит брони.
+func (r *PostgresRepo) GetBookingPayment(ctx context.Context, bookingID int64) (models.Payment, error) {
+	const op = "storage.postgres.GetBookingPayment"
+
+	p, err := scanPayment(r.pool.QueryRow(
+		ctx,
+		`SELECT `+paymentColumns+` FROM payments WHERE booking_id = $1 ORDER BY id DESC LIMIT 1`,
+		bookingID,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Payment{}, fmt.Errorf("%s: %w", op, storage.ErrPaymentNotFound)
+		}
+
+		return models.Payment{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return p, nil
+}
+
+// UpdatePaymentStatus переводит платёж из статуса from в статус to.
+// Если платёж сейчас не в статусе from, возвращается storage.ErrPaymentStatus.
+func (r *PostgresRepo) UpdatePaymentStatus(ctx context.Context, id int64, from, to string) error {
+	const op = "storage.postgres.UpdatePaymentStatus"
+
+	cmdTag, err := r.pool.Exec(
+		ctx,
+		`UPDATE payments SET status = $3, updated_at = NOW() WHERE id = $1 AND status = $2`,
+		id,
+		from,
+		to,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrPaymentStatus)
+	}
+
+	return nil
+}
+
+// GetExpiredPayments возвращает не больше limit неоплаченных платежей, срок оплаты которых истёк к now.
+func (r *PostgresRepo) GetExpiredPayments(ctx context.Context, now time.Time, limit int) ([]models.Payment, error) {
+	const op = "storage.postgres.GetExpiredPayments"
+
+	rows, err := r.pool.Query(
+		ctx,
+		`SELECT `+paymentColumns+`
+		FROM payments
+		WHERE status = $1 AND expires_at <= $2
+		ORDER BY expires_at
+		LIMIT $3`,
+		models.PaymentPending,
+		now,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	payments := []models.Payment{}
+	for rows.Next() {
+		p, err := scanPayment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		payments = append(payments, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return payments, nil
+}
+
+func scanPayment(row pgx.Row) (models.Payment, error) {
+	var p models.Payment
+	err := row.Scan(&p.ID, &p.BookingID, &p.ProviderID, &p.Amount, &p.Currency, &p.Status, &p.PaymentURL, &p.ExpiresAt)
+
+	return p, err
+}
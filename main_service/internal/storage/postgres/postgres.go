@@ -2,17 +2,22 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"main_service/internal/config"
 	"main_service/internal/models"
 	"main_service/internal/storage"
+	"slices"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type PostgresRepo struct {
 	pool *pgxpool.Pool
+	// cleaningBuffer - время на подготовку стола после ухода гостей, в течение которого его нельзя занять.
+	cleaningBuffer time.Duration
 }
 
 // Connect создает подключение к базе данных и возвращает репозиторий.
@@ -36,78 +41,403 @@ func Connect(ctx context.Context, cfg *config.Config) (*PostgresRepo, error) {
 		return nil, fmt.Errorf("%s: failed to create pool: %w", op, err)
 	}
 
-	return &PostgresRepo{pool: pool}, nil
+	return &PostgresRepo{pool: pool, cleaningBuffer: cfg.Restaurant.CleaningBuffer}, nil
 }
 
-func (r *PostgresRepo) SaveBooking(ctx context.Context, booking models.Booking) error {
+// SaveBooking сохраняет бронь и записывает событие создания в историю брони.
+func (r *PostgresRepo) SaveBooking(ctx context.Context, booking models.Booking, actor models.Actor) (int64, error) {
 	const op = "storage.postgres.SaveBooking"
 
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := r.lockTables(ctx, tx, booking); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	status := booking.Status
+	if status == "" {
+		status = models.StatusConfirmed
+	}
+
+	var promoCodeID int64
+	if booking.PromoCode != "" {
+		if promoCodeID, err = redeemPromoCode(ctx, tx, booking.PromoCode); err != nil {
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
 	var id int64
-	err := r.pool.QueryRow(
+	err = tx.QueryRow(
 		ctx,
-		`INSERT INTO bookings (user_id, table_id, booking_time) VALUES ($1, $2, $3) RETURNING id;`,
+		`INSERT INTO bookings (user_id, table_id, table_ids, combination_id, booking_time, party_size, notes, occasion, allergens, series_id, status, ends_at, promo_code_id)
+		VALUES ($1, $2, $3, NULLIF($4::integer, 0), $5, $6, $7, $8, $9, NULLIF($10::bigint, 0), $11, $12, NULLIF($13::bigint, 0)) RETURNING id;`,
 		booking.UserID,
 		booking.TableID,
+		booking.TableIDs,
+		booking.CombinationID,
 		booking.BookingTime,
+		booking.PartySize,
+		booking.Notes,
+		booking.Occasion,
+		tags(booking.Allergens),
+		booking.SeriesID,
+		status,
+		booking.EndsAt,
+		promoCodeID,
 	).Scan(&id)
 	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
+		return 0, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return nil
+	after := &models.BookingSnapshot{
+		UserID:      booking.UserID,
+		TableID:     booking.TableID,
+		TableIDs:    booking.TableIDs,
+		BookingTime: booking.BookingTime,
+		PartySize:   booking.PartySize,
+		Status:      status,
+		IsActive:    true,
+	}
+
+	if err := saveEvent(ctx, tx, id, models.EventBookingCreated, actor, nil, after); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := savePreOrder(ctx, tx, id, booking.PreOrder); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
 }
 
-// GetBookings возвращает либо все брони, либо только активные в зависимости от переменной mode
-func (r *PostgresRepo) GetBookings(ctx context.Context, mode string) ([]models.BookingInfo, error) {
+// GetBookings возвращает брони, подходящие под фильтр: либо все, либо только активные в зависимости от filter.Mode.
+func (r *PostgresRepo) GetBookings(ctx context.Context, filter models.BookingFilter) ([]models.BookingInfo, error) {
 	const op = "storage.postgres.GetBookings"
 
+	var bookings []models.BookingInfo
+	err := r.IterateBookings(ctx, filter, func(b models.BookingInfo) error {
+		bookings = append(bookings, b)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return bookings, nil
+}
+
+// IterateBookings построчно читает брони, подходящие под фильтр, и передаёт каждую в fn,
+// не загружая весь результат в память. Ошибка из fn прерывает чтение.
+func (r *PostgresRepo) IterateBookings(ctx context.Context, filter models.BookingFilter, fn func(models.BookingInfo) error) error {
+	const op = "storage.postgres.IterateBookings"
+
 	rows, err := r.pool.Query(
 		ctx,
-		`SELECT b.booking_time, b.table_id, u.email, u.first_name, u.last_name
+		`SELECT `+bookingInfoColumns+`
 		FROM bookings b
 		JOIN users u ON u.id = b.user_id
-		WHERE ($1 = 'all' OR (b.is_active = TRUE AND $1 = 'active'))
+		WHERE ($1 = 'all' OR (b.is_active = TRUE AND $1 = 'active') OR (b.status = $4 AND $1 = 'pending'))
+			AND ($2::timestamp IS NULL OR b.booking_time >= $2)
+			AND ($3::timestamp IS NULL OR b.booking_time < $3)
 		ORDER BY b.booking_time`,
-		mode,
+		filter.Mode,
+		filter.From,
+		filter.To,
+		models.StatusPendingApproval,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("%s: %w", op, err)
+		return fmt.Errorf("%s: %w", op, err)
 	}
 	defer rows.Close()
 
-	var bookings []models.BookingInfo
 	for rows.Next() {
-		var b models.BookingInfo
-		if err := rows.Scan(&b.BookingTime, &b.TableID, &b.Email, &b.FirstName, &b.LastName); err != nil {
-			return nil, fmt.Errorf("%s: %w", op, err)
+		b, err := scanBookingInfo(rows)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if err := fn(b); err != nil {
+			return err
 		}
-		bookings = append(bookings, b)
 	}
 
-	return bookings, nil
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
 }
 
-func (r *PostgresRepo) DeleteBooking(ctx context.Context, tableId int16, bookingTime time.Time) error {
+// GetBookingByID возвращает бронь вместе с данными гостя.
+func (r *PostgresRepo) GetBookingByID(ctx context.Context, bookingID int64) (models.BookingInfo, error) {
+	const op = "storage.postgres.GetBookingByID"
+
+	row := r.pool.QueryRow(
+		ctx,
+		`SELECT `+bookingInfoColumns+`
+		FROM bookings b
+		JOIN users u ON u.id = b.user_id
+		WHERE b.id = $1`,
+		bookingID,
+	)
+
+	b, err := scanBookingInfo(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.BookingInfo{}, fmt.Errorf("%s: %w", op, storage.ErrBookingNotFound)
+		}
+
+		return models.BookingInfo{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return b, nil
+}
+
+// DeleteBooking деактивирует бронь, записывает событие отмены в историю и возвращает отменённую бронь.
+func (r *PostgresRepo) DeleteBooking(ctx context.Context, tableId int16, bookingTime time.Time, actor models.Actor) (models.Booking, error) {
 	const op = "storage.postgres.DeleteBooking"
 
-	cmdTag, err := r.pool.Exec(
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return models.Booking{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	booking := models.Booking{
+		BookingTime: bookingTime,
+	}
+
+	// Бронь комбинации столов можно отменить по любому из её столов.
+	var prevStatus string
+	err = tx.QueryRow(
 		ctx,
-		`UPDATE bookings 
-		SET is_active = FALSE 
-		WHERE table_id = $1 AND booking_time = $2 AND is_active = $3`,
+		`UPDATE bookings b
+		SET is_active = FALSE, status = $4
+		FROM bookings old
+		WHERE old.id = b.id AND $1 = ANY(b.table_ids) AND b.booking_time = $2 AND b.is_active = $3
+		RETURNING b.id, b.user_id, b.table_id, b.table_ids, b.party_size, old.status`,
 		tableId,
 		bookingTime,
 		true,
+		models.StatusCancelled,
+	).Scan(&booking.ID, &booking.UserID, &booking.TableID, &booking.TableIDs, &booking.PartySize, &prevStatus)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Booking{}, fmt.Errorf("%s: %w", op, storage.ErrBookingNotFound)
+		}
+
+		return models.Booking{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	before := &models.BookingSnapshot{
+		UserID:      booking.UserID,
+		TableID:     booking.TableID,
+		TableIDs:    booking.TableIDs,
+		BookingTime: booking.BookingTime,
+		PartySize:   booking.PartySize,
+		Status:      prevStatus,
+		IsActive:    true,
+	}
+	after := *before
+	after.Status = models.StatusCancelled
+	after.IsActive = false
+
+	if err := saveEvent(ctx, tx, booking.ID, models.EventBookingCancelled, actor, before, &after); err != nil {
+		return models.Booking{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := releasePromoCode(ctx, tx, booking.ID); err != nil {
+		return models.Booking{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return models.Booking{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return booking, nil
+}
+
+// UpdateBookingStatus переводит бронь из статуса from в статус to и записывает изменение в историю брони.
+// Бронь остаётся активной, только если новый статус держит стол (confirmed, pending_approval или pending_payment).
+// Если бронь сейчас не в статусе from, возвращается storage.ErrUnexpectedStatus.
+func (r *PostgresRepo) UpdateBookingStatus(ctx context.Context, bookingID int64, from, to string, actor models.Actor) (models.Booking, error) {
+	const op = "storage.postgres.UpdateBookingStatus"
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return models.Booking{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	booking, err := updateBookingStatus(ctx, tx, bookingID, from, to, actor)
+	if err != nil {
+		return models.Booking{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return models.Booking{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return booking, nil
+}
+
+// updateBookingStatus меняет статус брони в транзакции tx, см. UpdateBookingStatus.
+func updateBookingStatus(ctx context.Context, tx pgx.Tx, bookingID int64, from, to string, actor models.Actor) (models.Booking, error) {
+	var (
+		booking    models.Booking
+		prevStatus string
+		isActive   bool
+	)
+
+	err := tx.QueryRow(
+		ctx,
+		`SELECT id, user_id, table_id, table_ids, booking_time, party_size, status, is_active
+		FROM bookings
+		WHERE id = $1
+		FOR UPDATE`,
+		bookingID,
+	).Scan(
+		&booking.ID,
+		&booking.UserID,
+		&booking.TableID,
+		&booking.TableIDs,
+		&booking.BookingTime,
+		&booking.PartySize,
+		&prevStatus,
+		&isActive,
 	)
 	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Booking{}, storage.ErrBookingNotFound
+		}
+
+		return models.Booking{}, err
 	}
 
-	if cmdTag.RowsAffected() == 0 {
-		return fmt.Errorf("%s: %w", op, storage.ErrBookingNotFound)
+	if !isActive || prevStatus != from {
+		return models.Booking{}, storage.ErrUnexpectedStatus
 	}
 
-	return nil
+	keepActive := to == models.StatusConfirmed || to == models.StatusPendingApproval || to == models.StatusPendingPayment
+
+	_, err = tx.Exec(
+		ctx,
+		`UPDATE bookings SET is_active = $3, status = $2 WHERE id = $1`,
+		bookingID,
+		to,
+		keepActive,
+	)
+	if err != nil {
+		return models.Booking{}, err
+	}
+
+	before := &models.BookingSnapshot{
+		UserID:      booking.UserID,
+		TableID:     booking.TableID,
+		TableIDs:    booking.TableIDs,
+		BookingTime: booking.BookingTime,
+		PartySize:   booking.PartySize,
+		Status:      prevStatus,
+		IsActive:    true,
+	}
+	after := *before
+	after.Status = to
+	after.IsActive = keepActive
+
+	if err := saveEvent(ctx, tx, booking.ID, models.EventBookingChanged, actor, before, &after); err != nil {
+		return models.Booking{}, err
+	}
+
+	if to == models.StatusCancelled || to == models.StatusRejected {
+		if err := releasePromoCode(ctx, tx, booking.ID); err != nil {
+			return models.Booking{}, err
+		}
+	}
+	booking.Status = to
+
+	return booking, nil
+}
+
+// CheckInBooking отмечает, что гости подтверждённой брони пришли и сели за стол.
+func (r *PostgresRepo) CheckInBooking(ctx context.Context, bookingID int64, actor models.Actor) (models.Booking, error) {
+	const op = "storage.postgres.CheckInBooking"
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return models.Booking{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	var (
+		booking   models.Booking
+		isActive  bool
+		checkedIn bool
+	)
+
+	err = tx.QueryRow(
+		ctx,
+		`SELECT id, user_id, table_id, table_ids, booking_time, party_size, status, is_active, checked_in_at IS NOT NULL
+		FROM bookings
+		WHERE id = $1
+		FOR UPDATE`,
+		bookingID,
+	).Scan(
+		&booking.ID,
+		&booking.UserID,
+		&booking.TableID,
+		&booking.TableIDs,
+		&booking.BookingTime,
+		&booking.PartySize,
+		&booking.Status,
+		&isActive,
+		&checkedIn,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Booking{}, fmt.Errorf("%s: %w", op, storage.ErrBookingNotFound)
+		}
+
+		return models.Booking{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !isActive || checkedIn || booking.Status != models.StatusConfirmed {
+		return models.Booking{}, fmt.Errorf("%s: %w", op, storage.ErrUnexpectedStatus)
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE bookings SET checked_in_at = NOW() WHERE id = $1`, bookingID)
+	if err != nil {
+		return models.Booking{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	before := &models.BookingSnapshot{
+		UserID:      booking.UserID,
+		TableID:     booking.TableID,
+		TableIDs:    booking.TableIDs,
+		BookingTime: booking.BookingTime,
+		PartySize:   booking.PartySize,
+		Status:      booking.Status,
+		IsActive:    true,
+	}
+	after := *before
+	after.CheckedIn = true
+
+	if err := saveEvent(ctx, tx, booking.ID, models.EventBookingChanged, actor, before, &after); err != nil {
+		return models.Booking{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return models.Booking{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return booking, nil
 }
 
 func (r *PostgresRepo) IsBookingOwner(ctx context.Context, tableID int16, bookingTime time.Time, userID int64) (bool, error) {
@@ -119,7 +449,7 @@ func (r *PostgresRepo) IsBookingOwner(ctx context.Context, tableID int16, bookin
 		`SELECT EXISTS(
 			SELECT 1 
 			FROM bookings 
-			WHERE table_id = $1 AND booking_time = $2 AND user_id = $3
+			WHERE $1 = ANY(table_ids) AND booking_time = $2 AND user_id = $3
 		)`,
 		tableID,
 		bookingTime,
@@ -133,6 +463,318 @@ func (r *PostgresRepo) IsBookingOwner(ctx context.Context, tableID int16, bookin
 	return exists, nil
 }
 
+// GetActiveBookings возвращает активные брони, столы которых заняты после from, в порядке времени брони.
+// По ним восстанавливается кэш броней в Redis.
+func (r *PostgresRepo) GetActiveBookings(ctx context.Context, from time.Time) ([]models.Booking, error) {
+	const op = "storage.postgres.GetActiveBookings"
+
+	rows, err := r.pool.Query(
+		ctx,
+		`SELECT id, user_id, table_id, table_ids, booking_time, ends_at, party_size, status, COALESCE(series_id, 0)
+		FROM bookings
+		WHERE is_active = TRUE AND ends_at > $1
+		ORDER BY booking_time, id`,
+		from,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	bookings := []models.Booking{}
+	for rows.Next() {
+		var b models.Booking
+		if err := rows.Scan(
+			&b.ID,
+			&b.UserID,
+			&b.TableID,
+			&b.TableIDs,
+			&b.BookingTime,
+			&b.EndsAt,
+			&b.PartySize,
+			&b.Status,
+			&b.SeriesID,
+		); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		bookings = append(bookings, b)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return bookings, nil
+}
+
+// GetBookingHistory возвращает историю изменений брони в хронологическом порядке.
+func (r *PostgresRepo) GetBookingHistory(ctx context.Context, bookingID int64) ([]models.BookingEvent, error) {
+	const op = "storage.postgres.GetBookingHistory"
+
+	var exists bool
+	err := r.pool.QueryRow(
+		ctx,
+		`SELECT EXISTS(SELECT 1 FROM bookings WHERE id = $1)`,
+		bookingID,
+	).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !exists {
+		return nil, fmt.Errorf("%s: %w", op, storage.ErrBookingNotFound)
+	}
+
+	rows, err := r.pool.Query(
+		ctx,
+		`SELECT id, booking_id, event_type, actor_id, actor_role, before_data, after_data, created_at
+		FROM booking_events
+		WHERE booking_id = $1
+		ORDER BY created_at, id`,
+		bookingID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	events := []models.BookingEvent{}
+	for rows.Next() {
+		var e models.BookingEvent
+		if err := rows.Scan(
+			&e.ID,
+			&e.BookingID,
+			&e.EventType,
+			&e.ActorID,
+			&e.ActorRole,
+			&e.Before,
+			&e.After,
+			&e.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		events = append(events, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return events, nil
+}
+
+// committedEvents - условие, что событие записано транзакцией старше самой старой открытой.
+// Такие транзакции уже завершены, и в ленте перед прочитанными событиями ничего не появится.
+const committedEvents = `tx_id < pg_snapshot_xmin(pg_current_snapshot())`
+
+// GetBookingEventsAfter возвращает до limit событий всех броней после позиции after в порядке ленты.
+// События транзакций, которые ещё могут быть открыты, не возвращаются, пока те не завершатся.
+func (r *PostgresRepo) GetBookingEventsAfter(ctx context.Context, after models.EventCursor, limit int) ([]models.BookingEvent, error) {
+	const op = "storage.postgres.GetBookingEventsAfter"
+
+	rows, err := r.pool.Query(
+		ctx,
+		`SELECT id, booking_id, event_type, actor_id, actor_role, before_data, after_data, created_at, tx_id::text::bigint
+		FROM booking_events
+		WHERE (tx_id, id) > ($1::bigint::text::xid8, $2)
+		  AND `+committedEvents+`
+		ORDER BY tx_id, id
+		LIMIT $3`,
+		after.TxID,
+		after.ID,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	events := []models.BookingEvent{}
+	for rows.Next() {
+		var e models.BookingEvent
+		if err := rows.Scan(
+			&e.ID,
+			&e.BookingID,
+			&e.EventType,
+			&e.ActorID,
+			&e.ActorRole,
+			&e.Before,
+			&e.After,
+			&e.CreatedAt,
+			&e.TxID,
+		); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		events = append(events, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return events, nil
+}
+
+// GetLastBookingEventCursor возвращает позицию последнего завершённого события в ленте,
+// нулевую - если событий нет.
+func (r *PostgresRepo) GetLastBookingEventCursor(ctx context.Context) (models.EventCursor, error) {
+	const op = "storage.postgres.GetLastBookingEventCursor"
+
+	var cursor models.EventCursor
+	err := r.pool.QueryRow(
+		ctx,
+		`SELECT tx_id::text::bigint, id
+		FROM booking_events
+		WHERE `+committedEvents+`
+		ORDER BY tx_id DESC, id DESC
+		LIMIT 1`,
+	).Scan(&cursor.TxID, &cursor.ID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.EventCursor{}, nil
+	}
+	if err != nil {
+		return models.EventCursor{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return cursor, nil
+}
+
+// GetBookingEventCursor возвращает позицию события eventID в ленте.
+func (r *PostgresRepo) GetBookingEventCursor(ctx context.Context, eventID int64) (models.EventCursor, error) {
+	const op = "storage.postgres.GetBookingEventCursor"
+
+	cursor := models.EventCursor{ID: eventID}
+	err := r.pool.QueryRow(
+		ctx,
+		`SELECT tx_id::text::bigint FROM booking_events WHERE id = $1`,
+		eventID,
+	).Scan(&cursor.TxID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.EventCursor{}, fmt.Errorf("%s: %w", op, storage.ErrUnknownEventID)
+	}
+	if err != nil {
+		return models.EventCursor{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return cursor, nil
+}
+
+// bookingInfoColumns - колонки для scanBookingInfo, b - bookings, u - users.
+const bookingInfoColumns = `b.id, b.user_id, b.booking_time, b.ends_at, b.table_id, b.table_ids, b.combination_id, b.party_size, b.status,
+	b.notes, b.occasion, b.allergens, b.series_id, b.checked_in_at, u.email, u.first_name, u.last_name,
+	COALESCE((SELECT p.code FROM promo_codes p WHERE p.id = b.promo_code_id), '')`
+
+// scanBookingInfo читает строку, выбранную по bookingInfoColumns.
+func scanBookingInfo(row pgx.Row) (models.BookingInfo, error) {
+	var b models.BookingInfo
+	err := row.Scan(
+		&b.ID,
+		&b.UserID,
+		&b.BookingTime,
+		&b.EndsAt,
+		&b.TableID,
+		&b.TableIDs,
+		&b.CombinationID,
+		&b.PartySize,
+		&b.Status,
+		&b.Notes,
+		&b.Occasion,
+		&b.Allergens,
+		&b.SeriesID,
+		&b.CheckedInAt,
+		&b.Email,
+		&b.FirstName,
+		&b.LastName,
+		&b.PromoCode,
+	)
+
+	return b, err
+}
+
+// tags заменяет nil на пустой срез, чтобы в колонку TEXT[] NOT NULL не попал NULL.
+func tags(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+
+	return values
+}
+
+// lockTables блокирует столы брони до конца транзакции и проверяет, что ни один из них не занят
+// активной бронью, которая пересекается с визитом с учётом времени на подготовку стола.
+// Блокировки берутся в порядке возрастания id стола, чтобы параллельные брони
+// пересекающихся комбинаций не приводили к взаимоблокировке.
+func (r *PostgresRepo) lockTables(ctx context.Context, tx pgx.Tx, booking models.Booking) error {
+	sorted := slices.Clone(booking.TableIDs)
+	slices.Sort(sorted)
+
+	for _, tableID := range sorted {
+		_, err := tx.Exec(
+			ctx,
+			`SELECT pg_advisory_xact_lock(hashtext($1))`,
+			fmt.Sprintf("table:%d", tableID),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	var booked bool
+	err := tx.QueryRow(
+		ctx,
+		`SELECT EXISTS(
+			SELECT 1
+			FROM bookings
+			WHERE is_active = TRUE AND table_ids && $1 AND booking_time < $3 AND ends_at > $2
+		)`,
+		booking.TableIDs,
+		booking.BookingTime.Add(-r.cleaningBuffer),
+		booking.EndsAt.Add(r.cleaningBuffer),
+	).Scan(&booked)
+	if err != nil {
+		return err
+	}
+
+	if booked {
+		return storage.ErrTableIsBooked
+	}
+
+	return nil
+}
+
+// saveEvent записывает событие в историю брони в рамках переданной транзакции и ставит его в очередь
+// доставки активным вебхукам, подписанным на этот тип событий. Доставки коммитятся вместе с событием,
+// поэтому ни одно событие не теряется, в каком бы порядке ни завершались транзакции.
+func saveEvent(
+	ctx context.Context,
+	tx pgx.Tx,
+	bookingID int64,
+	eventType string,
+	actor models.Actor,
+	before, after *models.BookingSnapshot,
+) error {
+	_, err := tx.Exec(
+		ctx,
+		`WITH e AS (
+			INSERT INTO booking_events (booking_id, event_type, actor_id, actor_role, before_data, after_data)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING id, event_type
+		)
+		INSERT INTO webhook_deliveries (webhook_id, event_id)
+		SELECT w.id, e.id
+		FROM e
+		JOIN webhooks w ON w.is_active = TRUE AND e.event_type = ANY(w.event_types)`,
+		bookingID,
+		eventType,
+		actor.ID,
+		actor.Role,
+		before,
+		after,
+	)
+
+	return err
+}
+
 // Close закрывает соединение с базой данных.
 func (r *PostgresRepo) Close() {
 	r.pool.Close()
@@ -0,0 +1,105 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// GetPreOrder возвращает предзаказ брони. Пустой срез - предзаказа нет.
+func (r *PostgresRepo) GetPreOrder(ctx context.Context, bookingID int64) ([]models.PreOrderItem, error) {
+	const op = "storage.postgres.GetPreOrder"
+
+	rows, err := r.pool.Query(
+		ctx,
+		`SELECT dish_id, name, quantity, price
+		FROM booking_preorders
+		WHERE booking_id = $1
+		ORDER BY dish_id`,
+		bookingID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	items := []models.PreOrderItem{}
+	for rows.Next() {
+		var item models.PreOrderItem
+		if err := rows.Scan(&item.DishID, &item.Name, &item.Quantity, &item.Price); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return items, nil
+}
+
+// ReplacePreOrder заменяет предзаказ активной брони на items. Пустой items удаляет предзаказ.
+// Если бронь уже не активна, возвращается storage.ErrUnexpectedStatus.
+func (r *PostgresRepo) ReplacePreOrder(ctx context.Context, bookingID int64, items []models.PreOrderItem) error {
+	const op = "storage.postgres.ReplacePreOrder"
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Блокировка брони не даёт изменить предзаказ параллельно с её отменой.
+	var isActive bool
+	err = tx.QueryRow(ctx, `SELECT is_active FROM bookings WHERE id = $1 FOR UPDATE`, bookingID).Scan(&isActive)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%s: %w", op, storage.ErrBookingNotFound)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !isActive {
+		return fmt.Errorf("%s: %w", op, storage.ErrUnexpectedStatus)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM booking_preorders WHERE booking_id = $1`, bookingID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := savePreOrder(ctx, tx, bookingID, items); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// savePreOrder записывает блюда предзаказа брони в транзакции tx.
+func savePreOrder(ctx context.Context, tx pgx.Tx, bookingID int64, items []models.PreOrderItem) error {
+	for _, item := range items {
+		_, err := tx.Exec(
+			ctx,
+			`INSERT INTO booking_preorders (booking_id, dish_id, name, quantity, price) VALUES ($1, $2, $3, $4, $5)`,
+			bookingID,
+			item.DishID,
+			item.Name,
+			item.Quantity,
+			item.Price,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
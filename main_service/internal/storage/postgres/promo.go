@@ -0,0 +1,159 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// uniqueViolation - код ошибки Postgres при нарушении уникальности.
+const uniqueViolation = "23505"
+
+const promoCodeColumns = `id, code, description, valid_from, valid_until, max_uses, used_count, weekdays, is_active, created_by`
+
+// SavePromoCode сохраняет промокод и возвращает его с присвоенным id.
+// Если такой код уже есть, возвращается storage.ErrPromoCodeExists.
+func (r *PostgresRepo) SavePromoCode(ctx context.Context, code models.PromoCode) (models.PromoCode, error) {
+	const op = "storage.postgres.SavePromoCode"
+
+	err := r.pool.QueryRow(
+		ctx,
+		`INSERT INTO promo_codes (code, description, valid_from, valid_until, max_uses, weekdays, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`,
+		code.Code,
+		code.Description,
+		code.ValidFrom,
+		code.ValidUntil,
+		code.MaxUses,
+		tags(code.Weekdays),
+		code.CreatedBy,
+	).Scan(&code.ID)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return models.PromoCode{}, fmt.Errorf("%s: %w", op, storage.ErrPromoCodeExists)
+		}
+
+		return models.PromoCode{}, fmt.Errorf("%s: %w", op, err)
+	}
+	code.Weekdays = tags(code.Weekdays)
+	code.IsActive = true
+
+	return code, nil
+}
+
+// GetPromoCodes возвращает все промокоды, новые первыми.
+func (r *PostgresRepo) GetPromoCodes(ctx context.Context) ([]models.PromoCode, error) {
+	const op = "storage.postgres.GetPromoCodes"
+
+	rows, err := r.pool.Query(ctx, `SELECT `+promoCodeColumns+` FROM promo_codes ORDER BY id DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	codes := []models.PromoCode{}
+	for rows.Next() {
+		c, err := scanPromoCode(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		codes = append(codes, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return codes, nil
+}
+
+// GetPromoCode возвращает промокод по его коду.
+func (r *PostgresRepo) GetPromoCode(ctx context.Context, code string) (models.PromoCode, error) {
+	const op = "storage.postgres.GetPromoCode"
+
+	c, err := scanPromoCode(r.pool.QueryRow(ctx, `SELECT `+promoCodeColumns+` FROM promo_codes WHERE code = $1`, code))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.PromoCode{}, fmt.Errorf("%s: %w", op, storage.ErrPromoCodeNotFound)
+		}
+
+		return models.PromoCode{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return c, nil
+}
+
+// DeactivatePromoCode выключает промокод. Брони, к которым он уже применён, не меняются.
+func (r *PostgresRepo) DeactivatePromoCode(ctx context.Context, id int64) error {
+	const op = "storage.postgres.DeactivatePromoCode"
+
+	cmdTag, err := r.pool.Exec(ctx, `UPDATE promo_codes SET is_active = FALSE WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrPromoCodeNotFound)
+	}
+
+	return nil
+}
+
+// redeemPromoCode засчитывает использование промокода в транзакции брони и возвращает его id.
+// Условие на число использований проверяется в том же UPDATE, поэтому параллельные брони не превысят лимит.
+func redeemPromoCode(ctx context.Context, tx pgx.Tx, code string) (int64, error) {
+	var id int64
+	err := tx.QueryRow(
+		ctx,
+		`UPDATE promo_codes SET used_count = used_count + 1
+		WHERE code = $1 AND is_active = TRUE AND (max_uses IS NULL OR used_count < max_uses)
+		RETURNING id`,
+		code,
+	).Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, storage.ErrPromoCodeExhausted
+		}
+
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// releasePromoCode возвращает использование промокода, если бронь отменена и гость им так и не воспользовался.
+func releasePromoCode(ctx context.Context, tx pgx.Tx, bookingID int64) error {
+	_, err := tx.Exec(
+		ctx,
+		`UPDATE promo_codes p SET used_count = p.used_count - 1
+		FROM bookings b
+		WHERE b.id = $1 AND p.id = b.promo_code_id AND p.used_count > 0`,
+		bookingID,
+	)
+
+	return err
+}
+
+func scanPromoCode(row pgx.Row) (models.PromoCode, error) {
+	var c models.PromoCode
+	err := row.Scan(
+		&c.ID,
+		&c.Code,
+		&c.Description,
+		&c.ValidFrom,
+		&c.ValidUntil,
+		&c.MaxUses,
+		&c.UsedCount,
+		&c.Weekdays,
+		&c.IsActive,
+		&c.CreatedBy,
+	)
+
+	return c, err
+}
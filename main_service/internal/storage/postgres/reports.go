@@ -0,0 +1,157 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"main_service/internal/models"
+	"time"
+)
+
+// notHeldStatuses - статусы броней, по которым гости не приходили или ещё не ясно, придут ли: отменённые,
+// отклонённые админом, неявки и брони, депозит по которым не оплачен. Оплаченная бронь становится confirmed,
+// неоплаченная отменяется, поэтому pending_payment в отчётах не считается, пока депозит не внесён.
+var notHeldStatuses = []string{models.StatusCancelled, models.StatusRejected, models.StatusNoShow, models.StatusPendingPayment}
+
+// GetCovers возвращает количество броней и гостей за каждый день или час периода [from, to).
+// Брони в notHeldStatuses не учитываются.
+func (r *PostgresRepo) GetCovers(ctx context.Context, from, to time.Time, groupBy string) ([]models.CoversStat, error) {
+	const op = "storage.postgres.GetCovers"
+
+	rows, err := r.pool.Query(
+		ctx,
+		`SELECT date_trunc($3::text, booking_time) AS period, COUNT(*), COALESCE(SUM(party_size), 0)
+		FROM bookings
+		WHERE booking_time >= $1 AND booking_time < $2 AND status <> ALL($4)
+		GROUP BY period
+		ORDER BY period`,
+		from,
+		to,
+		groupBy,
+		notHeldStatuses,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	stats := []models.CoversStat{}
+	for rows.Next() {
+		var s models.CoversStat
+		if err := rows.Scan(&s.Period, &s.Bookings, &s.Covers); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		stats = append(stats, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return stats, nil
+}
+
+// GetBookedTableHoursPerDay возвращает, сколько часов за каждый день периода [from, to) столы были заняты бронями.
+// Бронь занимает каждый свой стол от booking_time до ends_at, поэтому длительность учитывает правила для размера
+// компании и части дня. Дни без броней присутствуют в результате с нулевым значением.
+func (r *PostgresRepo) GetBookedTableHoursPerDay(ctx context.Context, from, to time.Time) ([]models.UtilisationStat, error) {
+	const op = "storage.postgres.GetBookedTableHoursPerDay"
+
+	rows, err := r.pool.Query(
+		ctx,
+		`SELECT d.day,
+			COALESCE(SUM(cardinality(b.table_ids) * EXTRACT(EPOCH FROM b.ends_at - b.booking_time)), 0) / 3600
+		FROM generate_series($1::timestamp, $2::timestamp - INTERVAL '1 day', INTERVAL '1 day') AS d(day)
+		LEFT JOIN bookings b
+			ON date_trunc('day', b.booking_time) = d.day AND b.status <> ALL($3)
+		GROUP BY d.day
+		ORDER BY d.day`,
+		from,
+		to,
+		notHeldStatuses,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	stats := []models.UtilisationStat{}
+	for rows.Next() {
+		var s models.UtilisationStat
+		if err := rows.Scan(&s.Date, &s.BookedHours); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		stats = append(stats, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return stats, nil
+}
+
+// GetBookingRates считает отмены, неявки и среднее время от создания брони до визита за период [from, to).
+func (r *PostgresRepo) GetBookingRates(ctx context.Context, from, to time.Time) (models.BookingRates, error) {
+	const op = "storage.postgres.GetBookingRates"
+
+	var rates models.BookingRates
+	err := r.pool.QueryRow(
+		ctx,
+		`SELECT
+			COUNT(*),
+			COUNT(*) FILTER (WHERE status = $3),
+			COUNT(*) FILTER (WHERE status = $4),
+			COUNT(*) FILTER (WHERE status = $5),
+			COALESCE(AVG(EXTRACT(EPOCH FROM booking_time - created_at)) / 3600, 0)
+		FROM bookings
+		WHERE booking_time >= $1 AND booking_time < $2`,
+		from,
+		to,
+		models.StatusCancelled,
+		models.StatusNoShow,
+		models.StatusCompleted,
+	).Scan(&rates.Total, &rates.Cancelled, &rates.NoShow, &rates.Completed, &rates.AvgLeadTimeHours)
+	if err != nil {
+		return models.BookingRates{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return rates, nil
+}
+
+// GetBusiestTables возвращает столы с наибольшим количеством броней за период [from, to), брони в notHeldStatuses не учитываются.
+func (r *PostgresRepo) GetBusiestTables(ctx context.Context, from, to time.Time, limit int) ([]models.TableStat, error) {
+	const op = "storage.postgres.GetBusiestTables"
+
+	rows, err := r.pool.Query(
+		ctx,
+		`SELECT t.table_id, COUNT(*) AS bookings, COALESCE(SUM(b.party_size), 0)
+		FROM bookings b, unnest(b.table_ids) AS t(table_id)
+		WHERE b.booking_time >= $1 AND b.booking_time < $2 AND b.status <> ALL($3)
+		GROUP BY t.table_id
+		ORDER BY bookings DESC, t.table_id
+		LIMIT $4`,
+		from,
+		to,
+		notHeldStatuses,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	stats := []models.TableStat{}
+	for rows.Next() {
+		var s models.TableStat
+		if err := rows.Scan(&s.TableID, &s.Bookings, &s.Covers); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		stats = append(stats, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,133 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// SaveReview сохраняет отзыв о завершённом визите и возвращает его с присвоенным id.
+// Если брони нет - storage.ErrBookingNotFound, визит не завершён - storage.ErrUnexpectedStatus,
+// отзыв по брони уже оставлен - storage.ErrReviewExists.
+func (r *PostgresRepo) SaveReview(ctx context.Context, review models.Review) (models.Review, error) {
+	const op = "storage.postgres.SaveReview"
+
+	err := r.pool.QueryRow(
+		ctx,
+		`INSERT INTO reviews (booking_id, rating, comment)
+		SELECT id, $2, $3 FROM bookings WHERE id = $1 AND status = $4
+		RETURNING id, created_at`,
+		review.BookingID,
+		review.Rating,
+		review.Comment,
+		models.StatusCompleted,
+	).Scan(&review.ID, &review.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return models.Review{}, fmt.Errorf("%s: %w", op, storage.ErrReviewExists)
+		}
+
+		if errors.Is(err, pgx.ErrNoRows) {
+			var exists bool
+			err := r.pool.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM bookings WHERE id = $1)`, review.BookingID).Scan(&exists)
+			if err != nil {
+				return models.Review{}, fmt.Errorf("%s: %w", op, err)
+			}
+
+			if !exists {
+				return models.Review{}, fmt.Errorf("%s: %w", op, storage.ErrBookingNotFound)
+			}
+
+			return models.Review{}, fmt.Errorf("%s: %w", op, storage.ErrUnexpectedStatus)
+		}
+
+		return models.Review{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return review, nil
+}
+
+// GetRatingsByDay возвращает количество отзывов и среднюю оценку за каждый день визитов периода [from, to).
+// Дни без отзывов в результат не попадают.
+func (r *PostgresRepo) GetRatingsByDay(ctx context.Context, from, to time.Time) ([]models.RatingStat, error) {
+	const op = "storage.postgres.GetRatingsByDay"
+
+	rows, err := r.pool.Query(
+		ctx,
+		`SELECT date_trunc('day', b.booking_time) AS day, COUNT(*), AVG(rv.rating)::float8
+		FROM reviews rv
+		JOIN bookings b ON b.id = rv.booking_id
+		WHERE b.booking_time >= $1 AND b.booking_time < $2
+		GROUP BY day
+		ORDER BY day`,
+		from,
+		to,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	stats := []models.RatingStat{}
+	for rows.Next() {
+		var (
+			s   models.RatingStat
+			day time.Time
+		)
+		if err := rows.Scan(&day, &s.Reviews, &s.AverageRating); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		s.Date = &day
+		stats = append(stats, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return stats, nil
+}
+
+// GetRatingsByTable возвращает количество отзывов и среднюю оценку по каждому столу за период [from, to).
+// Отзыв о брони комбинации столов учитывается у каждого её стола.
+func (r *PostgresRepo) GetRatingsByTable(ctx context.Context, from, to time.Time) ([]models.RatingStat, error) {
+	const op = "storage.postgres.GetRatingsByTable"
+
+	rows, err := r.pool.Query(
+		ctx,
+		`SELECT t.table_id, COUNT(*), AVG(rv.rating)::float8
+		FROM reviews rv
+		JOIN bookings b ON b.id = rv.booking_id, unnest(b.table_ids) AS t(table_id)
+		WHERE b.booking_time >= $1 AND b.booking_time < $2
+		GROUP BY t.table_id
+		ORDER BY t.table_id`,
+		from,
+		to,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	stats := []models.RatingStat{}
+	for rows.Next() {
+		var s models.RatingStat
+		if err := rows.Scan(&s.TableID, &s.Reviews, &s.AverageRating); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		stats = append(stats, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// SaveSeries сохраняет повторяющуюся бронь и возвращает её id.
+func (r *PostgresRepo) SaveSeries(ctx context.Context, series models.BookingSeries) (int64, error) {
+	const op = "storage.postgres.SaveSeries"
+
+	var id int64
+	err := r.pool.QueryRow(
+		ctx,
+		`INSERT INTO booking_series (user_id, table_id, starts_at, interval_weeks, occurrences)
+		VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+		series.UserID,
+		series.TableID,
+		series.StartsAt,
+		series.IntervalWeeks,
+		series.Occurrences,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// GetSeries возвращает повторяющуюся бронь по id.
+func (r *PostgresRepo) GetSeries(ctx context.Context, seriesID int64) (models.BookingSeries, error) {
+	const op = "storage.postgres.GetSeries"
+
+	var s models.BookingSeries
+	err := r.pool.QueryRow(
+		ctx,
+		`SELECT id, user_id, table_id, starts_at, interval_weeks, occurrences, is_active
+		FROM booking_series
+		WHERE id = $1`,
+		seriesID,
+	).Scan(&s.ID, &s.UserID, &s.TableID, &s.StartsAt, &s.IntervalWeeks, &s.Occurrences, &s.IsActive)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.BookingSeries{}, fmt.Errorf("%s: %w", op, storage.ErrSeriesNotFound)
+		}
+
+		return models.BookingSeries{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return s, nil
+}
+
+// GetActiveSeriesBookings возвращает активные даты серии, которые не прошли к now. Время брони хранится
+// по часам ресторана без пояса, поэтому now тоже передаётся по часам ресторана.
+func (r *PostgresRepo) GetActiveSeriesBookings(ctx context.Context, seriesID int64, now time.Time) ([]models.Booking, error) {
+	const op = "storage.postgres.GetActiveSeriesBookings"
+
+	rows, err := r.pool.Query(
+		ctx,
+		`SELECT id, user_id, table_id, table_ids, booking_time, party_size
+		FROM bookings
+		WHERE series_id = $1 AND is_active = TRUE AND booking_time >= $2
+		ORDER BY booking_time`,
+		seriesID,
+		now,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var bookings []models.Booking
+	for rows.Next() {
+		b := models.Booking{SeriesID: seriesID}
+		if err := rows.Scan(&b.ID, &b.UserID, &b.TableID, &b.TableIDs, &b.BookingTime, &b.PartySize); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		bookings = append(bookings, b)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return bookings, nil
+}
+
+// DeactivateSeries помечает серию отменённой. Сами даты отменяются отдельно.
+func (r *PostgresRepo) DeactivateSeries(ctx context.Context, seriesID int64) error {
+	const op = "storage.postgres.DeactivateSeries"
+
+	cmdTag, err := r.pool.Exec(
+		ctx,
+		`UPDATE booking_series SET is_active = FALSE WHERE id = $1`,
+		seriesID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrSeriesNotFound)
+	}
+
+	return nil
+}
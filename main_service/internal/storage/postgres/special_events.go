@@ -0,0 +1,332 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const specialEventColumns = `id, title, description, starts_at, ends_at, capacity, seats_reserved, price, is_active, created_by`
+
+// SaveSpecialEvent сохраняет событие и возвращает его с присвоенным id.
+func (r *PostgresRepo) SaveSpecialEvent(ctx context.Context, event models.SpecialEvent) (models.SpecialEvent, error) {
+	const op = "storage.postgres.SaveSpecialEvent"
+
+	err := r.pool.QueryRow(
+		ctx,
+		`INSERT INTO special_events (title, description, starts_at, ends_at, capacity, price, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`,
+		event.Title,
+		event.Description,
+		event.StartsAt,
+		event.EndsAt,
+		event.Capacity,
+		event.Price,
+		event.CreatedBy,
+	).Scan(&event.ID)
+	if err != nil {
+		return models.SpecialEvent{}, fmt.Errorf("%s: %w", op, err)
+	}
+	event.SeatsReserved = 0
+	event.SeatsLeft = event.Capacity
+	event.IsActive = true
+
+	return event, nil
+}
+
+// GetUpcomingSpecialEvents возвращает действующие события, которые не начались к now, ближайшие первыми.
+// Время события хранится по часам ресторана без пояса, поэтому now тоже передаётся по часам ресторана.
+func (r *PostgresRepo) GetUpcomingSpecialEvents(ctx context.Context, now time.Time) ([]models.SpecialEvent, error) {
+	const op = "storage.postgres.GetUpcomingSpecialEvents"
+
+	rows, err := r.pool.Query(
+		ctx,
+		`SELECT `+specialEventColumns+`
+		FROM special_events
+		WHERE is_active = TRUE AND starts_at > $1
+		ORDER BY starts_at, id`,
+		now,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	events := []models.SpecialEvent{}
+	for rows.Next() {
+		e, err := scanSpecialEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		events = append(events, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return events, nil
+}
+
+// GetSpecialEvent возвращает событие по id, в том числе отменённое.
+func (r *PostgresRepo) GetSpecialEvent(ctx context.Context, id int64) (models.SpecialEvent, error) {
+	const op = "storage.postgres.GetSpecialEvent"
+
+	e, err := scanSpecialEvent(r.pool.QueryRow(ctx, `SELECT `+specialEventColumns+` FROM special_events WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.SpecialEvent{}, fmt.Errorf("%s: %w", op, storage.ErrEventNotFound)
+		}
+
+		return models.SpecialEvent{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return e, nil
+}
+
+// CancelSpecialEvent отменяет событие вместе со всеми резервациями мест на него.
+func (r *PostgresRepo) CancelSpecialEvent(ctx context.Context, id int64) error {
+	const op = "storage.postgres.CancelSpecialEvent"
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	cmdTag, err := tx.Exec(ctx, `UPDATE special_events SET is_active = FALSE, seats_reserved = 0 WHERE id = $1 AND is_active = TRUE`, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrEventNotFound)
+	}
+
+	_, err = tx.Exec(
+		ctx,
+		`UPDATE event_reservations SET is_active = FALSE, cancelled_at = NOW() WHERE event_id = $1 AND is_active = TRUE`,
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ReserveEventSeats резервирует места гостя на событии и возвращает резервацию с присвоенным id и стоимостью.
+// Свободные места проверяются и уменьшаются одним UPDATE, поэтому параллельные резервации не превысят вместимость.
+// Если событие отменено - storage.ErrEventNotFound, началось к now - storage.ErrEventStarted,
+// мест не хватает - storage.ErrEventSoldOut, у гостя уже есть места - storage.ErrReservationExists.
+func (r *PostgresRepo) ReserveEventSeats(ctx context.Context, reservation models.EventReservation, now time.Time) (models.EventReservation, error) {
+	const op = "storage.postgres.ReserveEventSeats"
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return models.EventReservation{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	var price int64
+	err = tx.QueryRow(
+		ctx,
+		`UPDATE special_events SET seats_reserved = seats_reserved + $2
+		WHERE id = $1 AND is_active = TRUE AND starts_at > $3 AND seats_reserved + $2 <= capacity
+		RETURNING price`,
+		reservation.EventID,
+		reservation.Seats,
+		now,
+	).Scan(&price)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.EventReservation{}, fmt.Errorf("%s: %w", op, reserveError(ctx, tx, reservation.EventID, now))
+		}
+
+		return models.EventReservation{}, fmt.Errorf("%s: %w", op, err)
+	}
+	reservation.Amount = price * int64(reservation.Seats)
+
+	err = tx.QueryRow(
+		ctx,
+		`INSERT INTO event_reservations (event_id, user_id, seats, amount)
+		VALUES ($1, $2, $3, $4) RETURNING id, created_at`,
+		reservation.EventID,
+		reservation.UserID,
+		reservation.Seats,
+		reservation.Amount,
+	).Scan(&reservation.ID, &reservation.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return models.EventReservation{}, fmt.Errorf("%s: %w", op, storage.ErrReservationExists)
+		}
+
+		return models.EventReservation{}, fmt.Errorf("%s: %w", op, err)
+	}
+	reservation.IsActive = true
+
+	if err := tx.Commit(ctx); err != nil {
+		return models.EventReservation{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return reservation, nil
+}
+
+// reserveError объясняет, почему не удалось зарезервировать места на событии.
+func reserveError(ctx context.Context, tx pgx.Tx, eventID int64, now time.Time) error {
+	var isActive, started bool
+	err := tx.QueryRow(
+		ctx,
+		`SELECT is_active, starts_at <= $2 FROM special_events WHERE id = $1`,
+		eventID,
+		now,
+	).Scan(&isActive, &started)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return storage.ErrEventNotFound
+		}
+
+		return err
+	}
+
+	switch {
+	case !isActive:
+		return storage.ErrEventNotFound
+	case started:
+		return storage.ErrEventStarted
+	default:
+		return storage.ErrEventSoldOut
+	}
+}
+
+// CancelEventReservation отменяет места гостя на событии и возвращает их в продажу,
+// если событие не началось к now. Если действующей резервации нет - storage.ErrReservationNotFound.
+func (r *PostgresRepo) CancelEventReservation(ctx context.Context, eventID, userID int64, now time.Time) error {
+	const op = "storage.postgres.CancelEventReservation"
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Блокировка события не даёт отменить места параллельно с его началом или отменой.
+	var started bool
+	err = tx.QueryRow(ctx, `SELECT starts_at <= $2 FROM special_events WHERE id = $1 FOR UPDATE`, eventID, now).Scan(&started)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%s: %w", op, storage.ErrEventNotFound)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if started {
+		return fmt.Errorf("%s: %w", op, storage.ErrEventStarted)
+	}
+
+	var seats int
+	err = tx.QueryRow(
+		ctx,
+		`UPDATE event_reservations SET is_active = FALSE, cancelled_at = NOW()
+		WHERE event_id = $1 AND user_id = $2 AND is_active = TRUE
+		RETURNING seats`,
+		eventID,
+		userID,
+	).Scan(&seats)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%s: %w", op, storage.ErrReservationNotFound)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE special_events SET seats_reserved = seats_reserved - $2 WHERE id = $1`, eventID, seats)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// GetEventReservations возвращает действующие резервации на событие с контактами гостей в порядке их создания.
+func (r *PostgresRepo) GetEventReservations(ctx context.Context, eventID int64) ([]models.EventReservation, error) {
+	const op = "storage.postgres.GetEventReservations"
+
+	rows, err := r.pool.Query(
+		ctx,
+		`SELECT er.id, er.event_id, er.user_id, er.seats, er.amount, er.is_active, er.created_at,
+			u.email, u.first_name, u.last_name
+		FROM event_reservations er
+		JOIN users u ON u.id = er.user_id
+		WHERE er.event_id = $1 AND er.is_active = TRUE
+		ORDER BY er.created_at, er.id`,
+		eventID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	reservations := []models.EventReservation{}
+	for rows.Next() {
+		var res models.EventReservation
+		err := rows.Scan(
+			&res.ID,
+			&res.EventID,
+			&res.UserID,
+			&res.Seats,
+			&res.Amount,
+			&res.IsActive,
+			&res.CreatedAt,
+			&res.Email,
+			&res.FirstName,
+			&res.LastName,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		reservations = append(reservations, res)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return reservations, nil
+}
+
+func scanSpecialEvent(row pgx.Row) (models.SpecialEvent, error) {
+	var e models.SpecialEvent
+	err := row.Scan(
+		&e.ID,
+		&e.Title,
+		&e.Description,
+		&e.StartsAt,
+		&e.EndsAt,
+		&e.Capacity,
+		&e.SeatsReserved,
+		&e.Price,
+		&e.IsActive,
+		&e.CreatedBy,
+	)
+	e.SeatsLeft = e.Capacity - e.SeatsReserved
+
+	return e, err
+}
@@ -0,0 +1,234 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// SaveCombination сохраняет комбинацию столов и возвращает её id.
+func (r *PostgresRepo) SaveCombination(ctx context.Context, combination models.TableCombination) (int, error) {
+	const op = "storage.postgres.SaveCombination"
+
+	var id int
+	err := r.pool.QueryRow(
+		ctx,
+		`INSERT INTO table_combinations (name, table_ids, capacity) VALUES ($1, $2, $3) RETURNING id`,
+		combination.Name,
+		combination.TableIDs,
+		combination.Capacity,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// GetCombinations возвращает все действующие комбинации столов.
+func (r *PostgresRepo) GetCombinations(ctx context.Context) ([]models.TableCombination, error) {
+	const op = "storage.postgres.GetCombinations"
+
+	rows, err := r.pool.Query(
+		ctx,
+		`SELECT id, name, table_ids, capacity
+		FROM table_combinations
+		WHERE is_active = TRUE
+		ORDER BY id`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	combinations := []models.TableCombination{}
+	for rows.Next() {
+		var c models.TableCombination
+		if err := rows.Scan(&c.ID, &c.Name, &c.TableIDs, &c.Capacity); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		combinations = append(combinations, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return combinations, nil
+}
+
+// GetCombination возвращает действующую комбинацию столов по id.
+func (r *PostgresRepo) GetCombination(ctx context.Context, id int) (models.TableCombination, error) {
+	const op = "storage.postgres.GetCombination"
+
+	var c models.TableCombination
+	err := r.pool.QueryRow(
+		ctx,
+		`SELECT id, name, table_ids, capacity
+		FROM table_combinations
+		WHERE id = $1 AND is_active = TRUE`,
+		id,
+	).Scan(&c.ID, &c.Name, &c.TableIDs, &c.Capacity)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.TableCombination{}, fmt.Errorf("%s: %w", op, storage.ErrCombinationNotFound)
+		}
+
+		return models.TableCombination{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return c, nil
+}
+
+// DeactivateCombination убирает комбинацию из доступных. Брони, сделанные на неё, сохраняются.
+func (r *PostgresRepo) DeactivateCombination(ctx context.Context, id int) error {
+	const op = "storage.postgres.DeactivateCombination"
+
+	cmdTag, err := r.pool.Exec(
+		ctx,
+		`UPDATE table_combinations SET is_active = FALSE WHERE id = $1 AND is_active = TRUE`,
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrCombinationNotFound)
+	}
+
+	return nil
+}
+
+// GetTables возвращает все столы ресторана вместе с их местом на плане зала.
+func (r *PostgresRepo) GetTables(ctx context.Context) ([]models.Table, error) {
+	const op = "storage.postgres.GetTables"
+
+	rows, err := r.pool.Query(
+		ctx,
+		`SELECT id, zone, x, y, width, height, rotation, shape, seats, attributes
+		FROM restaurant_tables
+		ORDER BY id`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	tables := []models.Table{}
+	for rows.Next() {
+		var t models.Table
+		if err := rows.Scan(&t.ID, &t.Zone, &t.X, &t.Y, &t.Width, &t.Height, &t.Rotation, &t.Shape, &t.Seats, &t.Attributes); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		tables = append(tables, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tables, nil
+}
+
+// SaveTable добавляет стол или обновляет его зал и место на плане зала.
+func (r *PostgresRepo) SaveTable(ctx context.Context, table models.Table) error {
+	const op = "storage.postgres.SaveTable"
+
+	_, err := r.pool.Exec(
+		ctx,
+		`INSERT INTO restaurant_tables (id, zone, x, y, width, height, rotation, shape, seats, attributes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (id) DO UPDATE SET
+			zone = EXCLUDED.zone,
+			x = EXCLUDED.x,
+			y = EXCLUDED.y,
+			width = EXCLUDED.width,
+			height = EXCLUDED.height,
+			rotation = EXCLUDED.rotation,
+			shape = EXCLUDED.shape,
+			seats = EXCLUDED.seats,
+			attributes = EXCLUDED.attributes`,
+		table.ID,
+		table.Zone,
+		table.X,
+		table.Y,
+		table.Width,
+		table.Height,
+		table.Rotation,
+		table.Shape,
+		table.Seats,
+		tags(table.Attributes),
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// GetFloorBookings возвращает активные брони, которые пересекаются с промежутком [from, to),
+// и брони гостей, которые уже сидят за столом и время брони которых закончилось не раньше seatedFrom.
+// Если бронь так и не закрыли, стол перестаёт считаться занятым, когда seatedFrom пройдёт конец брони.
+func (r *PostgresRepo) GetFloorBookings(ctx context.Context, from, to, seatedFrom time.Time) ([]models.BookingInfo, error) {
+	const op = "storage.postgres.GetFloorBookings"
+
+	rows, err := r.pool.Query(
+		ctx,
+		`SELECT `+bookingInfoColumns+`
+		FROM bookings b
+		JOIN users u ON u.id = b.user_id
+		WHERE b.is_active = TRUE
+			AND ((b.booking_time < $2 AND b.ends_at > $1)
+				OR (b.checked_in_at IS NOT NULL AND b.booking_time < $2 AND b.ends_at > $3))
+		ORDER BY b.booking_time`,
+		from,
+		to,
+		seatedFrom,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	bookings := []models.BookingInfo{}
+	for rows.Next() {
+		b, err := scanBookingInfo(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		bookings = append(bookings, b)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return bookings, nil
+}
+
+// GetBookedTables возвращает столы, которые нельзя занять на визит [from, to): у них есть активная бронь,
+// пересекающаяся с визитом с учётом времени на подготовку стола.
+func (r *PostgresRepo) GetBookedTables(ctx context.Context, from, to time.Time) ([]int16, error) {
+	const op = "storage.postgres.GetBookedTables"
+
+	var tableIDs []int16
+	err := r.pool.QueryRow(
+		ctx,
+		`SELECT COALESCE(array_agg(DISTINCT t.table_id), '{}')
+		FROM bookings b, unnest(b.table_ids) AS t(table_id)
+		WHERE b.is_active = TRUE AND b.booking_time < $2 AND b.ends_at > $1`,
+		from.Add(-r.cleaningBuffer),
+		to.Add(r.cleaningBuffer),
+	).Scan(&tableIDs)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tableIDs, nil
+}
@@ -0,0 +1,328 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+	"time"
+)
+
+// SaveWebhook сохраняет подписку на события броней и возвращает её id.
+func (r *PostgresRepo) SaveWebhook(ctx context.Context, webhook models.Webhook) (int64, error) {
+	const op = "storage.postgres.SaveWebhook"
+
+	var id int64
+	err := r.pool.QueryRow(
+		ctx,
+		`INSERT INTO webhooks (url, event_types, secret) VALUES ($1, $2, $3) RETURNING id`,
+		webhook.URL,
+		webhook.EventTypes,
+		webhook.Secret,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// GetWebhooks возвращает все подписки без секретов.
+func (r *PostgresRepo) GetWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	const op = "storage.postgres.GetWebhooks"
+
+	rows, err := r.pool.Query(
+		ctx,
+		`SELECT id, url, event_types, is_active, failure_count, created_at
+		FROM webhooks
+		ORDER BY id`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	webhooks := []models.Webhook{}
+	for rows.Next() {
+		var w models.Webhook
+		if err := rows.Scan(&w.ID, &w.URL, &w.EventTypes, &w.IsActive, &w.FailureCount, &w.CreatedAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		webhooks = append(webhooks, w)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return webhooks, nil
+}
+
+// DeleteWebhook удаляет подписку вместе с её доставками.
+func (r *PostgresRepo) DeleteWebhook(ctx context.Context, id int64) error {
+	const op = "storage.postgres.DeleteWebhook"
+
+	cmdTag, err := r.pool.Exec(ctx, `DELETE FROM webhooks WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrWebhookNotFound)
+	}
+
+	return nil
+}
+
+// EnableWebhook снова включает подписку и сбрасывает счётчик неудачных доставок.
+func (r *PostgresRepo) EnableWebhook(ctx context.Context, id int64) error {
+	const op = "storage.postgres.EnableWebhook"
+
+	cmdTag, err := r.pool.Exec(
+		ctx,
+		`UPDATE webhooks SET is_active = TRUE, failure_count = 0 WHERE id = $1`,
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrWebhookNotFound)
+	}
+
+	return nil
+}
+
+// ClaimWebhookDeliveries забирает до limit доставок, время которых наступило к now, и откладывает их
+// до leaseUntil, чтобы другой экземпляр сервиса не отправил их параллельно.
+func (r *PostgresRepo) ClaimWebhookDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.WebhookDelivery, error) {
+	const op = "storage.postgres.ClaimWebhookDeliveries"
+
+	rows, err := r.pool.Query(
+		ctx,
+		`UPDATE webhook_deliveries d
+		SET next_attempt_at = $2
+		FROM webhooks w, booking_events e
+		WHERE d.id IN (
+				SELECT d2.id
+				FROM webhook_deliveries d2
+				JOIN webhooks w2 ON w2.id = d2.webhook_id AND w2.is_active = TRUE
+				WHERE d2.status = $4 AND d2.next_attempt_at <= $1
+				ORDER BY d2.id
+				LIMIT $3
+				FOR UPDATE OF d2 SKIP LOCKED
+			)
+			AND w.id = d.webhook_id
+			AND e.id = d.event_id
+		RETURNING d.id, d.webhook_id, w.url, w.secret, d.attempts,
+			e.id, e.booking_id, e.event_type, e.actor_id, e.actor_role, e.before_data, e.after_data, e.created_at`,
+		now,
+		leaseUntil,
+		limit,
+		models.DeliveryPending,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var deliveries []models.WebhookDelivery
+	for rows.Next() {
+		var d models.WebhookDelivery
+		if err := rows.Scan(
+			&d.ID,
+			&d.WebhookID,
+			&d.URL,
+			&d.Secret,
+			&d.Attempts,
+			&d.Event.ID,
+			&d.Event.BookingID,
+			&d.Event.EventType,
+			&d.Event.ActorID,
+			&d.Event.ActorRole,
+			&d.Event.Before,
+			&d.Event.After,
+			&d.Event.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		deliveries = append(deliveries, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return deliveries, nil
+}
+
+// SaveWebhookAttempt записывает попытку доставки и увеличивает счётчик попыток доставки.
+func (r *PostgresRepo) SaveWebhookAttempt(ctx context.Context, attempt models.WebhookAttempt) error {
+	const op = "storage.postgres.SaveWebhookAttempt"
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(
+		ctx,
+		`INSERT INTO webhook_attempts (delivery_id, attempt, status_code, error, duration_ms)
+		VALUES ($1, $2, $3, $4, $5)`,
+		attempt.DeliveryID,
+		attempt.Attempt,
+		attempt.StatusCode,
+		attempt.Error,
+		attempt.DurationMs,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE webhook_deliveries SET attempts = $2 WHERE id = $1`, attempt.DeliveryID, attempt.Attempt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// CompleteWebhookDelivery отмечает доставку успешной и сбрасывает счётчик неудач подписки.
+func (r *PostgresRepo) CompleteWebhookDelivery(ctx context.Context, deliveryID, webhookID int64) error {
+	const op = "storage.postgres.CompleteWebhookDelivery"
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `UPDATE webhook_deliveries SET status = $2 WHERE id = $1`, deliveryID, models.DeliveryDelivered)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE webhooks SET failure_count = 0 WHERE id = $1`, webhookID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// RescheduleWebhookDelivery назначает следующую попытку доставки на время at.
+func (r *PostgresRepo) RescheduleWebhookDelivery(ctx context.Context, deliveryID int64, at time.Time) error {
+	const op = "storage.postgres.RescheduleWebhookDelivery"
+
+	_, err := r.pool.Exec(ctx, `UPDATE webhook_deliveries SET next_attempt_at = $2 WHERE id = $1`, deliveryID, at)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// FailWebhookDelivery отмечает доставку неудачной после всех попыток. Если у подписки набралось
+// disableAfter неудачных доставок подряд, она отключается. Возвращает true, если подписка отключена.
+func (r *PostgresRepo) FailWebhookDelivery(ctx context.Context, deliveryID, webhookID int64, disableAfter int) (bool, error) {
+	const op = "storage.postgres.FailWebhookDelivery"
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `UPDATE webhook_deliveries SET status = $2 WHERE id = $1`, deliveryID, models.DeliveryFailed)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var disabled bool
+	err = tx.QueryRow(
+		ctx,
+		`UPDATE webhooks
+		SET failure_count = failure_count + 1,
+			is_active = is_active AND failure_count + 1 < $2
+		WHERE id = $1
+		RETURNING NOT is_active`,
+		webhookID,
+		disableAfter,
+	).Scan(&disabled)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return disabled, nil
+}
+
+// GetWebhookAttempts возвращает последние limit попыток доставки по подписке, новые первыми.
+func (r *PostgresRepo) GetWebhookAttempts(ctx context.Context, webhookID int64, limit int) ([]models.WebhookAttempt, error) {
+	const op = "storage.postgres.GetWebhookAttempts"
+
+	var exists bool
+	err := r.pool.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM webhooks WHERE id = $1)`, webhookID).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !exists {
+		return nil, fmt.Errorf("%s: %w", op, storage.ErrWebhookNotFound)
+	}
+
+	rows, err := r.pool.Query(
+		ctx,
+		`SELECT a.id, a.delivery_id, d.event_id, e.event_type, a.attempt, a.status_code, a.error, a.duration_ms, a.attempted_at
+		FROM webhook_attempts a
+		JOIN webhook_deliveries d ON d.id = a.delivery_id
+		JOIN booking_events e ON e.id = d.event_id
+		WHERE d.webhook_id = $1
+		ORDER BY a.id DESC
+		LIMIT $2`,
+		webhookID,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	attempts := []models.WebhookAttempt{}
+	for rows.Next() {
+		var a models.WebhookAttempt
+		if err := rows.Scan(
+			&a.ID,
+			&a.DeliveryID,
+			&a.EventID,
+			&a.EventType,
+			&a.Attempt,
+			&a.StatusCode,
+			&a.Error,
+			&a.DurationMs,
+			&a.AttemptedAt,
+		); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		attempts = append(attempts, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return attempts, nil
+}
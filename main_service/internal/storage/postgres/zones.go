@@ -0,0 +1,92 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+)
+
+// SaveZoneSchedule сохраняет расписание зала и возвращает его с присвоенным id.
+func (r *PostgresRepo) SaveZoneSchedule(ctx context.Context, schedule models.ZoneSchedule) (models.ZoneSchedule, error) {
+	const op = "storage.postgres.SaveZoneSchedule"
+
+	var exists bool
+	err := r.pool.QueryRow(
+		ctx,
+		`SELECT EXISTS(SELECT 1 FROM restaurant_tables WHERE zone = $1)`,
+		schedule.Zone,
+	).Scan(&exists)
+	if err != nil {
+		return models.ZoneSchedule{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !exists {
+		return models.ZoneSchedule{}, fmt.Errorf("%s: %w", op, storage.ErrZoneNotFound)
+	}
+
+	err = r.pool.QueryRow(
+		ctx,
+		`INSERT INTO zone_schedules (zone, starts_on, ends_on, opens_at, closes_at, created_by)
+		VALUES ($1, $2::text::date, $3::text::date, $4::text::time, $5::text::time, $6) RETURNING id`,
+		schedule.Zone,
+		schedule.StartsOn,
+		schedule.EndsOn,
+		schedule.OpensAt,
+		schedule.ClosesAt,
+		schedule.CreatedBy,
+	).Scan(&schedule.ID)
+	if err != nil {
+		return models.ZoneSchedule{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return schedule, nil
+}
+
+// GetZoneSchedules возвращает расписания всех залов.
+func (r *PostgresRepo) GetZoneSchedules(ctx context.Context) ([]models.ZoneSchedule, error) {
+	const op = "storage.postgres.GetZoneSchedules"
+
+	rows, err := r.pool.Query(
+		ctx,
+		`SELECT id, zone, to_char(starts_on, 'YYYY-MM-DD'), to_char(ends_on, 'YYYY-MM-DD'),
+			to_char(opens_at, 'HH24:MI'), to_char(closes_at, 'HH24:MI'), created_by
+		FROM zone_schedules
+		ORDER BY zone, starts_on, opens_at`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	schedules := []models.ZoneSchedule{}
+	for rows.Next() {
+		var s models.ZoneSchedule
+		if err := rows.Scan(&s.ID, &s.Zone, &s.StartsOn, &s.EndsOn, &s.OpensAt, &s.ClosesAt, &s.CreatedBy); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		schedules = append(schedules, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return schedules, nil
+}
+
+// DeleteZoneSchedule удаляет расписание зала. Если у зала не осталось расписаний, он открыт всегда.
+func (r *PostgresRepo) DeleteZoneSchedule(ctx context.Context, id int64) error {
+	const op = "storage.postgres.DeleteZoneSchedule"
+
+	cmdTag, err := r.pool.Exec(ctx, `DELETE FROM zone_schedules WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrScheduleNotFound)
+	}
+
+	return nil
+}
@@ -0,0 +1,161 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"main_service/internal/storage"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	reservePacingScript = `
+		-- KEYS[1] = pacingKey
+		-- ARGV[1] = гостей в брони
+		-- ARGV[2] = максимум гостей за интервал, 0 - без ограничения
+		-- ARGV[3] = максимум броней за интервал, 0 - без ограничения
+		-- ARGV[4] = конец интервала (unix ms)
+
+		local covers = tonumber(redis.call("HGET", KEYS[1], "covers") or "0")
+		local bookings = tonumber(redis.call("HGET", KEYS[1], "bookings") or "0")
+		local party = tonumber(ARGV[1])
+		local maxCovers = tonumber(ARGV[2])
+		local maxBookings = tonumber(ARGV[3])
+
+		if (maxCovers > 0 and covers + party > maxCovers) or (maxBookings > 0 and bookings + 1 > maxBookings) then
+			return redis.error_reply("PACING_LIMIT")
+		end
+
+		redis.call("HINCRBY", KEYS[1], "covers", party)
+		redis.call("HINCRBY", KEYS[1], "bookings", 1)
+		redis.call("PEXPIREAT", KEYS[1], ARGV[4])
+		return "OK"
+	`
+
+	releasePacingScript = `
+		-- KEYS[1] = pacingKey
+		-- ARGV[1] = гостей в брони
+
+		if redis.call("EXISTS", KEYS[1]) == 0 then
+			return "OK"
+		end
+
+		local bookings = redis.call("HINCRBY", KEYS[1], "bookings", -1)
+		redis.call("HINCRBY", KEYS[1], "covers", -tonumber(ARGV[1]))
+		if bookings <= 0 then
+			redis.call("DEL", KEYS[1])
+		end
+		return "OK"
+	`
+
+	restorePacingScript = `
+		-- KEYS[1] = pacingKey
+		-- ARGV[1] = гостей в интервале
+		-- ARGV[2] = броней в интервале
+		-- ARGV[3] = конец интервала (unix ms)
+
+		-- Существующий счётчик уже учитывает брони, сделанные после его создания, и не меняется.
+		if redis.call("EXISTS", KEYS[1]) == 1 then
+			return 0
+		end
+
+		redis.call("HSET", KEYS[1], "covers", ARGV[1], "bookings", ARGV[2])
+		redis.call("PEXPIREAT", KEYS[1], ARGV[3])
+		return 1
+	`
+)
+
+// PacingSlot - интервал кухни, на который приходится бронь.
+type PacingSlot struct {
+	// Start - начало интервала по часам ресторана, End - его конец, после которого счётчик не нужен.
+	Start       time.Time
+	End         time.Time
+	Covers      int
+	MaxCovers   int
+	MaxBookings int
+}
+
+// ReservePacing атомарно учитывает бронь в счётчиках интервала. Если интервал уже заполнен,
+// возвращает storage.ErrPacingLimit.
+func (r *RedisRepo) ReservePacing(ctx context.Context, slot PacingSlot) error {
+	const op = "storage.redis.ReservePacing"
+
+	err := r.client.Eval(
+		ctx,
+		reservePacingScript,
+		[]string{pacingKey(slot.Start)},
+		slot.Covers,
+		slot.MaxCovers,
+		slot.MaxBookings,
+		slot.End.UnixMilli(),
+	).Err()
+	if err != nil {
+		if strings.Contains(err.Error(), "PACING_LIMIT") {
+			return fmt.Errorf("%s: %w", op, storage.ErrPacingLimit)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ReleasePacing убирает отменённую бронь из счётчиков интервала.
+func (r *RedisRepo) ReleasePacing(ctx context.Context, slot PacingSlot) error {
+	const op = "storage.redis.ReleasePacing"
+
+	if err := r.client.Eval(ctx, releasePacingScript, []string{pacingKey(slot.Start)}, slot.Covers).Err(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// RestorePacing создаёт счётчики интервала по броням из Postgres, если их нет в Redis.
+// Возвращает true, если счётчики созданы.
+func (r *RedisRepo) RestorePacing(ctx context.Context, slot PacingSlot, bookings int) (bool, error) {
+	const op = "storage.redis.RestorePacing"
+
+	created, err := r.client.Eval(
+		ctx,
+		restorePacingScript,
+		[]string{pacingKey(slot.Start)},
+		slot.Covers,
+		bookings,
+		slot.End.UnixMilli(),
+	).Int()
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return created == 1, nil
+}
+
+// GetPacing возвращает, сколько гостей и броней уже ожидается в интервале, начинающемся в start.
+func (r *RedisRepo) GetPacing(ctx context.Context, start time.Time) (int, int, error) {
+	const op = "storage.redis.GetPacing"
+
+	values, err := r.client.HMGet(ctx, pacingKey(start), "covers", "bookings").Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return 0, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	counters := make([]int, 2)
+	for i, v := range values {
+		if s, ok := v.(string); ok {
+			if counters[i], err = strconv.Atoi(s); err != nil {
+				return 0, 0, fmt.Errorf("%s: %w", op, err)
+			}
+		}
+	}
+
+	return counters[0], counters[1], nil
+}
+
+func pacingKey(start time.Time) string {
+	return "pacing:" + start.Format(slotLayout)
+}
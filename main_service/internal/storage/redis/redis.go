@@ -3,6 +3,7 @@ package redis
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"main_service/internal/storage"
 	"strings"
@@ -14,35 +15,111 @@ import (
 const (
 	redisScript = `
 		-- KEYS[1] = userKey
-		-- KEYS[2] = tableKey
+		-- KEYS[2..n] = tableKeys, все столы брони
 		-- ARGV[1] = value
-		-- ARGV[2] = ttl (ms)
+		-- ARGV[2] = ttl ключа пользователя (ms)
+		-- ARGV[3] = "1", если пользователь может иметь только одну бронь
+		-- ARGV[4] = слот брони - время начала по часам ресторана
+		-- ARGV[5] = начало брони (unix ms)
+		-- ARGV[6] = когда стол освободится с учётом подготовки (unix ms)
+		-- ARGV[7] = текущее время (unix ms)
+
+		-- В ключе стола хранится отсортированное множество его броней:
+		-- элемент - "слот|начало", score - время освобождения стола.
 
 		-- Проверяем, есть ли уже бронь у пользователя
-		if redis.call("EXISTS", KEYS[1]) == 1 then
+		if ARGV[3] == "1" and redis.call("EXISTS", KEYS[1]) == 1 then
 			return redis.error_reply("USER_ALREADY_BOOKED")
 		end
 
-		-- Проверяем, занят ли стол
-		if redis.call("EXISTS", KEYS[2]) == 1 then
-			return redis.error_reply("TABLE_ALREADY_BOOKED")
+		-- Проверяем, не пересекается ли визит с бронями столов, которые освободятся после его начала
+		local to = tonumber(ARGV[6])
+		for i = 2, #KEYS do
+			local members = redis.call("ZRANGEBYSCORE", KEYS[i], "(" .. ARGV[5], "+inf")
+			for _, member in ipairs(members) do
+				if tonumber(string.match(member, "|(%d+)$")) < to then
+					return redis.error_reply("TABLE_ALREADY_BOOKED")
+				end
+			end
+		end
+
+		-- Добавляем бронь. В ключе пользователя храним ключ первого стола и слот брони,
+		-- чтобы при отмене снимать только свою блокировку.
+		if ARGV[3] == "1" then
+			redis.call("SET", KEYS[1], KEYS[2] .. "@" .. ARGV[4], "PX", ARGV[2])
+		end
+		for i = 2, #KEYS do
+			redis.call("ZREMRANGEBYSCORE", KEYS[i], "-inf", ARGV[7])
+			redis.call("ZADD", KEYS[i], ARGV[6], ARGV[4] .. "|" .. ARGV[5])
+
+			-- Ключ стола живёт, пока не освободится стол по последней брони
+			local last = redis.call("ZRANGE", KEYS[i], -1, -1, "WITHSCORES")
+			redis.call("PEXPIREAT", KEYS[i], last[2])
+		end
+		return "OK"
+	`
+
+	deleteScript = `
+		-- KEYS[1] = userKey
+		-- KEYS[2..n] = tableKeys
+		-- ARGV[1] = слот брони
+
+		local prefix = ARGV[1] .. "|"
+		for i = 2, #KEYS do
+			for _, member in ipairs(redis.call("ZRANGE", KEYS[i], 0, -1)) do
+				if string.sub(member, 1, #prefix) == prefix then
+					redis.call("ZREM", KEYS[i], member)
+				end
+			end
+		end
+
+		-- Ключ пользователя снимаем, только если он относится к этой брони
+		if redis.call("GET", KEYS[1]) == KEYS[2] .. "@" .. ARGV[1] then
+			redis.call("DEL", KEYS[1])
+		end
+		return "OK"
+	`
+
+	restoreScript = `
+		-- KEYS[1] = userKey
+		-- KEYS[2..n] = tableKeys
+		-- ARGV[1] = ttl ключа пользователя (ms)
+		-- ARGV[2] = "1", если бронь ограничивает пользователя одной бронью
+		-- ARGV[3] = слот брони
+		-- ARGV[4] = начало брони (unix ms)
+		-- ARGV[5] = когда стол освободится с учётом подготовки (unix ms)
+
+		-- Бронь уже сохранена в Postgres, поэтому пересечения не проверяются, а повторное
+		-- восстановление той же брони ничего не меняет.
+		if ARGV[2] == "1" then
+			redis.call("SET", KEYS[1], KEYS[2] .. "@" .. ARGV[3], "PX", ARGV[1], "NX")
 		end
+		for i = 2, #KEYS do
+			redis.call("ZADD", KEYS[i], ARGV[5], ARGV[3] .. "|" .. ARGV[4])
 
-		-- Добавляем бронь
-		redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2])
-		redis.call("SET", KEYS[2], ARGV[1], "PX", ARGV[2])
+			local last = redis.call("ZRANGE", KEYS[i], -1, -1, "WITHSCORES")
+			redis.call("PEXPIREAT", KEYS[i], last[2])
+		end
 		return "OK"
 	`
 )
 
+// slotLayout - формат слота брони. Слот строится по часам ресторана, а не по абсолютному времени,
+// потому что время брони, прочитанное из Postgres, теряет часовой пояс.
+const slotLayout = "20060102T1504"
+
 type RedisRepo struct {
 	client *redis.Client
 }
 
 type Booking struct {
-	TableID int64     `json:"table_id"`
-	UserID  int64     `json:"user_id"`
-	Time    time.Time `json:"booking_time"`
+	TableID int64 `json:"table_id"`
+	// TableIDs - все столы брони, если их несколько. Пустой - занят только TableID.
+	TableIDs []int64   `json:"table_ids,omitempty"`
+	UserID   int64     `json:"user_id"`
+	Time     time.Time `json:"booking_time"`
+	// Until - когда столы освободятся: конец визита плюс время на подготовку стола.
+	Until time.Time `json:"until"`
 }
 
 func New(ctx context.Context, address string, password string, db int) (*RedisRepo, error) {
@@ -61,68 +138,212 @@ func New(ctx context.Context, address string, password string, db int) (*RedisRe
 	return &RedisRepo{client: rdb}, nil
 }
 
-// SaveBooking сохраняет бронь, ключом является время:столик:userID
+// SaveBooking сохраняет бронь, ключами являются userID и каждый стол брони
 func (r *RedisRepo) SaveBooking(ctx context.Context, booking Booking) error {
 	const op = "storage.redis.SaveBooking"
 
-	userKey := fmt.Sprintf("booking:user:%d", booking.UserID)
-	tableKey := fmt.Sprintf("booking:%s:table%d:uid:%d",
-		booking.Time.Format("2006-01-02"),
-		booking.TableID,
-		booking.UserID,
-	)
+	if err := r.save(ctx, booking, true); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
 
-	// TTL до конца дня
-	endOfBookingDay := time.Date(
-		booking.Time.Year(),
-		booking.Time.Month(),
-		booking.Time.Day(),
-		23, 59, 59, 0,
-		booking.Time.Location(),
-	)
+	return nil
+}
 
-	ttl := time.Until(endOfBookingDay)
-	if ttl <= 0 {
-		return storage.ErrPastDate
+// SaveTableBooking занимает только столы, не ограничивая пользователя одной бронью.
+// Используется для дат повторяющейся брони.
+func (r *RedisRepo) SaveTableBooking(ctx context.Context, booking Booking) error {
+	const op = "storage.redis.SaveTableBooking"
+
+	if err := r.save(ctx, booking, false); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
 	}
 
-	data, err := json.Marshal(booking)
+	return nil
+}
+
+// DeleteBooking удаляет бронь по времени и id столов
+func (r *RedisRepo) DeleteBooking(ctx context.Context, booking Booking) error {
+	return r.client.Eval(ctx, deleteScript, keys(booking), booking.Time.Format(slotLayout)).Err()
+}
+
+// RestoreBooking восстанавливает в Redis бронь, уже сохранённую в Postgres. Если checkUser, бронь
+// снова ограничивает пользователя, но только когда у него ещё нет ключа.
+func (r *RedisRepo) RestoreBooking(ctx context.Context, booking Booking, checkUser bool) error {
+	const op = "storage.redis.RestoreBooking"
+
+	ttl, err := bookingTTL(booking.Time)
+	if err != nil {
+		// День брони закончился, ключ пользователя не нужен.
+		checkUser = false
+		ttl = 0
+	}
+
+	userLimit := "0"
+	if checkUser {
+		userLimit = "1"
+	}
+
+	err = r.client.Eval(
+		ctx,
+		restoreScript,
+		keys(booking),
+		fmt.Sprintf("%d", ttl.Milliseconds()),
+		userLimit,
+		booking.Time.Format(slotLayout),
+		booking.Time.UnixMilli(),
+		booking.Until.UnixMilli(),
+	).Err()
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
+	return nil
+}
+
+// DeleteLegacyKeys удаляет ключи броней в прежнем формате: ключ стола был строкой с JSON брони,
+// а ключ пользователя хранил только ключ стола без слота. Скрипты с такими ключами не работают.
+// Возвращает количество удалённых ключей.
+func (r *RedisRepo) DeleteLegacyKeys(ctx context.Context) (int, error) {
+	const op = "storage.redis.DeleteLegacyKeys"
+
+	deleted := 0
+
+	tables := r.client.Scan(ctx, 0, "booking:table:*", 1000).Iterator()
+	for tables.Next(ctx) {
+		key := tables.Val()
+
+		keyType, err := r.client.Type(ctx, key).Result()
+		if err != nil {
+			return deleted, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if keyType == "zset" {
+			continue
+		}
+
+		if err := r.client.Del(ctx, key).Err(); err != nil {
+			return deleted, fmt.Errorf("%s: %w", op, err)
+		}
+		deleted++
+	}
+	if err := tables.Err(); err != nil {
+		return deleted, fmt.Errorf("%s: %w", op, err)
+	}
+
+	users := r.client.Scan(ctx, 0, "booking:user:*", 1000).Iterator()
+	for users.Next(ctx) {
+		key := users.Val()
+
+		value, err := r.client.Get(ctx, key).Result()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			return deleted, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if strings.Contains(value, "@") {
+			continue
+		}
+
+		if err := r.client.Del(ctx, key).Err(); err != nil {
+			return deleted, fmt.Errorf("%s: %w", op, err)
+		}
+		deleted++
+	}
+	if err := users.Err(); err != nil {
+		return deleted, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return deleted, nil
+}
+
+// save атомарно занимает все столы брони скриптом redisScript.
+func (r *RedisRepo) save(ctx context.Context, booking Booking, checkUser bool) error {
+	ttl, err := bookingTTL(booking.Time)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(booking)
+	if err != nil {
+		return err
+	}
+
 	ttlMs := fmt.Sprintf("%d", ttl.Milliseconds())
-	_, err = r.client.Eval(ctx, redisScript,
-		[]string{userKey, tableKey},
-		string(data), ttlMs,
-	).Result()
 
+	userLimit := "0"
+	if checkUser {
+		userLimit = "1"
+	}
+
+	_, err = r.client.Eval(
+		ctx,
+		redisScript,
+		keys(booking),
+		string(data),
+		ttlMs,
+		userLimit,
+		booking.Time.Format(slotLayout),
+		booking.Time.UnixMilli(),
+		booking.Until.UnixMilli(),
+		time.Now().UnixMilli(),
+	).Result()
 	if err != nil {
 		if strings.Contains(err.Error(), "USER_ALREADY_BOOKED") {
-			return fmt.Errorf("%s: %w", op, storage.ErrUserAlreadyBooked)
+			return storage.ErrUserAlreadyBooked
 		}
 		if strings.Contains(err.Error(), "TABLE_ALREADY_BOOKED") {
-			return fmt.Errorf("%s: %w", op, storage.ErrTableIsBooked)
+			return storage.ErrTableIsBooked
 		}
-		return fmt.Errorf("%s: %w", op, err)
+		return err
 	}
 
 	return nil
 }
 
-// DeleteBooking удаляет бронь по времени и id стола
-func (r *RedisRepo) DeleteBooking(ctx context.Context, booking Booking) error {
-	userKey := fmt.Sprintf("booking:user:%d", booking.UserID)
-	tableKey := fmt.Sprintf("booking:%s:table%d:uid:%d",
-		booking.Time.Format("2006-01-02"),
-		booking.TableID,
-		booking.UserID,
-	)
-
-	return r.client.Del(ctx, userKey, tableKey).Err()
-}
-
 // Close закрывает соединение с базой данных.
 func (r *RedisRepo) Close() {
 	r.client.Close()
 }
+
+func userKey(booking Booking) string {
+	return fmt.Sprintf("booking:user:%d", booking.UserID)
+}
+
+// keys возвращает ключ пользователя и ключи всех столов брони для скриптов.
+func keys(booking Booking) []string {
+	tableIDs := booking.TableIDs
+	if len(tableIDs) == 0 {
+		tableIDs = []int64{booking.TableID}
+	}
+
+	keys := []string{userKey(booking)}
+	for _, tableID := range tableIDs {
+		keys = append(keys, tableKey(tableID))
+	}
+
+	return keys
+}
+
+func tableKey(tableID int64) string {
+	return fmt.Sprintf("booking:table:%d", tableID)
+}
+
+// bookingTTL возвращает время жизни ключа пользователя - до конца дня брони.
+func bookingTTL(bookingTime time.Time) (time.Duration, error) {
+	endOfBookingDay := time.Date(
+		bookingTime.Year(),
+		bookingTime.Month(),
+		bookingTime.Day(),
+		23, 59, 59, 0,
+		bookingTime.Location(),
+	)
+
+	ttl := time.Until(endOfBookingDay)
+	if ttl <= 0 {
+		return 0, storage.ErrPastDate
+	}
+
+	return ttl, nil
+}
@@ -3,9 +3,44 @@ package storage
 import "errors"
 
 var (
-	ErrTableIsBooked     = errors.New("table is already booked")
-	ErrBookingNotFound   = errors.New("booking is not found")
-	ErrTableIsEmpty      = errors.New("bookings table is empty")
-	ErrPastDate          = errors.New("cannot create booking for a past date")
-	ErrUserAlreadyBooked = errors.New("user has already booked a table")
+	ErrTableIsBooked       = errors.New("table is already booked")
+	ErrBookingNotFound     = errors.New("booking is not found")
+	ErrTableIsEmpty        = errors.New("bookings table is empty")
+	ErrPastDate            = errors.New("cannot create booking for a past date")
+	ErrUserAlreadyBooked   = errors.New("user has already booked a table")
+	ErrUnexpectedStatus    = errors.New("booking status does not allow this action")
+	ErrTokenNotFound       = errors.New("calendar token is not found")
+	ErrSeriesNotFound      = errors.New("booking series is not found")
+	ErrCombinationNotFound = errors.New("table combination is not found")
+	ErrPartyTooLarge       = errors.New("party size exceeds table capacity")
+	ErrTableIsBlocked      = errors.New("table is blocked for this time")
+	ErrBlockNotFound       = errors.New("table block is not found")
+	ErrZoneNotFound        = errors.New("zone is not found")
+	ErrZoneClosed          = errors.New("zone is closed at this time")
+	ErrScheduleNotFound    = errors.New("zone schedule is not found")
+	ErrWebhookNotFound     = errors.New("webhook is not found")
+	ErrAttributesMismatch  = errors.New("table does not have the required attributes")
+	ErrNoSuitableTable     = errors.New("no free table matches the request")
+	ErrPacingLimit         = errors.New("kitchen pacing limit reached for this time")
+	ErrPaymentNotFound     = errors.New("payment is not found")
+	ErrPaymentStatus       = errors.New("payment status does not allow this action")
+	ErrUserNotFound        = errors.New("user is not found")
+	ErrUserBlocked         = errors.New("user is blocked from booking")
+	ErrPromoCodeNotFound   = errors.New("promo code is not found")
+	ErrPromoCodeExists     = errors.New("promo code already exists")
+	ErrPromoCodeInvalid    = errors.New("promo code is not valid for this booking")
+	ErrPromoCodeExhausted  = errors.New("promo code usage limit reached")
+	ErrCategoryNotFound    = errors.New("menu category is not found")
+	ErrCategoryNotEmpty    = errors.New("menu category still has dishes")
+	ErrDishNotFound        = errors.New("dish is not found")
+	ErrDishUnavailable     = errors.New("dish is not available for pre-order")
+	ErrPreOrderTooLarge    = errors.New("pre-order is too large for the party size")
+	ErrPreOrderClosed      = errors.New("pre-order can no longer be changed")
+	ErrEventNotFound       = errors.New("special event is not found")
+	ErrEventSoldOut        = errors.New("not enough seats left for the special event")
+	ErrEventStarted        = errors.New("special event has already started")
+	ErrReservationExists   = errors.New("user already has seats for the special event")
+	ErrReservationNotFound = errors.New("event reservation is not found")
+	ErrReviewExists        = errors.New("booking has already been reviewed")
+	ErrUnknownEventID      = errors.New("booking event is not found")
 )
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS booking_events (
+  id          BIGSERIAL PRIMARY KEY,
+  booking_id  BIGINT NOT NULL REFERENCES bookings(id) ON DELETE CASCADE,
+  event_type  VARCHAR(32) NOT NULL,
+  actor_id    BIGINT NOT NULL,
+  actor_role  VARCHAR(32) NOT NULL,
+  before_data JSONB,
+  after_data  JSONB,
+  created_at  TIMESTAMP NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_booking_events_booking_id ON booking_events (booking_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_booking_events_booking_id;
+DROP TABLE IF EXISTS booking_events;
+-- +goose StatementEnd
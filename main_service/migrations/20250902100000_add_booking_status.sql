@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE bookings
+  ADD COLUMN status     VARCHAR(32) NOT NULL DEFAULT 'confirmed',
+  ADD COLUMN party_size SMALLINT NOT NULL DEFAULT 2,
+  ADD COLUMN created_at TIMESTAMP NOT NULL DEFAULT NOW();
+
+UPDATE bookings SET status = 'cancelled' WHERE is_active = FALSE;
+
+-- Для старых броней время создания берём из истории, если оно там есть.
+UPDATE bookings b
+SET created_at = e.created_at
+FROM booking_events e
+WHERE e.booking_id = b.id AND e.event_type = 'created';
+
+CREATE INDEX IF NOT EXISTS idx_bookings_booking_time ON bookings (booking_time);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_bookings_booking_time;
+ALTER TABLE bookings
+  DROP COLUMN status,
+  DROP COLUMN party_size,
+  DROP COLUMN created_at;
+-- +goose StatementEnd
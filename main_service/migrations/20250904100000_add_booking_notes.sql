@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE bookings
+  ADD COLUMN notes     TEXT NOT NULL DEFAULT '',
+  ADD COLUMN occasion  VARCHAR(32) NOT NULL DEFAULT '',
+  ADD COLUMN allergens TEXT[] NOT NULL DEFAULT '{}';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE bookings
+  DROP COLUMN notes,
+  DROP COLUMN occasion,
+  DROP COLUMN allergens;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS booking_series (
+  id             BIGSERIAL PRIMARY KEY,
+  user_id        BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  table_id       SMALLINT NOT NULL,
+  starts_at      TIMESTAMP NOT NULL,
+  interval_weeks SMALLINT NOT NULL,
+  occurrences    SMALLINT NOT NULL,
+  is_active      BOOLEAN NOT NULL DEFAULT TRUE,
+  created_at     TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE bookings
+  ADD COLUMN series_id BIGINT REFERENCES booking_series(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_bookings_series_id ON bookings (series_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_bookings_series_id;
+ALTER TABLE bookings DROP COLUMN series_id;
+DROP TABLE IF EXISTS booking_series;
+-- +goose StatementEnd
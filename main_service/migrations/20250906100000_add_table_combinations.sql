@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS table_combinations (
+  id         SERIAL PRIMARY KEY,
+  name       VARCHAR(100) NOT NULL,
+  table_ids  SMALLINT[] NOT NULL,
+  capacity   SMALLINT NOT NULL,
+  is_active  BOOLEAN NOT NULL DEFAULT TRUE,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE bookings
+  ADD COLUMN table_ids      SMALLINT[] NOT NULL DEFAULT '{}',
+  ADD COLUMN combination_id INTEGER REFERENCES table_combinations(id);
+
+UPDATE bookings SET table_ids = ARRAY[table_id];
+
+CREATE INDEX IF NOT EXISTS idx_bookings_table_ids ON bookings USING GIN (table_ids);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_bookings_table_ids;
+ALTER TABLE bookings
+  DROP COLUMN table_ids,
+  DROP COLUMN combination_id;
+DROP TABLE IF EXISTS table_combinations;
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS restaurant_tables (
+  id   SMALLINT PRIMARY KEY,
+  zone VARCHAR(50) NOT NULL DEFAULT 'main'
+);
+
+INSERT INTO restaurant_tables (id)
+SELECT generate_series(1, 10)
+ON CONFLICT DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS table_blocks (
+  id         BIGSERIAL PRIMARY KEY,
+  table_ids  SMALLINT[] NOT NULL,
+  zone       VARCHAR(50) NOT NULL DEFAULT '',
+  starts_at  TIMESTAMP NOT NULL,
+  ends_at    TIMESTAMP NOT NULL,
+  reason     VARCHAR(200) NOT NULL DEFAULT '',
+  created_by BIGINT NOT NULL,
+  is_active  BOOLEAN NOT NULL DEFAULT TRUE,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_table_blocks_table_ids ON table_blocks USING GIN (table_ids);
+CREATE INDEX IF NOT EXISTS idx_table_blocks_ends_at ON table_blocks (ends_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS table_blocks;
+DROP TABLE IF EXISTS restaurant_tables;
+-- +goose StatementEnd
@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Координаты и размеры стола задаются в условных единицах сетки плана зала.
+ALTER TABLE restaurant_tables
+  ADD COLUMN x        REAL NOT NULL DEFAULT 0,
+  ADD COLUMN y        REAL NOT NULL DEFAULT 0,
+  ADD COLUMN width    REAL NOT NULL DEFAULT 1,
+  ADD COLUMN height   REAL NOT NULL DEFAULT 1,
+  ADD COLUMN rotation SMALLINT NOT NULL DEFAULT 0,
+  ADD COLUMN shape    VARCHAR(16) NOT NULL DEFAULT 'square',
+  ADD COLUMN seats    SMALLINT NOT NULL DEFAULT 4;
+
+ALTER TABLE bookings
+  ADD COLUMN checked_in_at TIMESTAMP;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE bookings
+  DROP COLUMN checked_in_at;
+ALTER TABLE restaurant_tables
+  DROP COLUMN x,
+  DROP COLUMN y,
+  DROP COLUMN width,
+  DROP COLUMN height,
+  DROP COLUMN rotation,
+  DROP COLUMN shape,
+  DROP COLUMN seats;
+-- +goose StatementEnd
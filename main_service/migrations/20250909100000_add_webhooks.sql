@@ -0,0 +1,57 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS webhooks (
+  id            BIGSERIAL PRIMARY KEY,
+  url           TEXT NOT NULL,
+  event_types   TEXT[] NOT NULL,
+  secret        TEXT NOT NULL,
+  is_active     BOOLEAN NOT NULL DEFAULT TRUE,
+  -- failure_count - сколько доставок подряд не удалось после всех повторов.
+  failure_count INTEGER NOT NULL DEFAULT 0,
+  created_at    TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+  id              BIGSERIAL PRIMARY KEY,
+  webhook_id      BIGINT NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+  event_id        BIGINT NOT NULL REFERENCES booking_events(id) ON DELETE CASCADE,
+  status          VARCHAR(16) NOT NULL DEFAULT 'pending',
+  attempts        INTEGER NOT NULL DEFAULT 0,
+  next_attempt_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  created_at      TIMESTAMP NOT NULL DEFAULT NOW(),
+  UNIQUE (webhook_id, event_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_pending
+  ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+
+CREATE TABLE IF NOT EXISTS webhook_attempts (
+  id           BIGSERIAL PRIMARY KEY,
+  delivery_id  BIGINT NOT NULL REFERENCES webhook_deliveries(id) ON DELETE CASCADE,
+  attempt      INTEGER NOT NULL,
+  status_code  INTEGER NOT NULL DEFAULT 0,
+  error        TEXT NOT NULL DEFAULT '',
+  duration_ms  INTEGER NOT NULL DEFAULT 0,
+  attempted_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_attempts_delivery_id ON webhook_attempts (delivery_id);
+
+-- Последнее событие истории броней, уже разложенное по доставкам. Единственная строка.
+CREATE TABLE IF NOT EXISTS webhook_cursor (
+  id            SMALLINT PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+  last_event_id BIGINT NOT NULL
+);
+
+INSERT INTO webhook_cursor (last_event_id)
+SELECT COALESCE(MAX(id), 0) FROM booking_events
+ON CONFLICT DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS webhook_cursor;
+DROP TABLE IF EXISTS webhook_attempts;
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhooks;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE restaurant_tables
+  ADD COLUMN attributes TEXT[] NOT NULL DEFAULT '{}';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE restaurant_tables
+  DROP COLUMN attributes;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS zone_schedules (
+  id         BIGSERIAL PRIMARY KEY,
+  zone       VARCHAR(50) NOT NULL,
+  starts_on  DATE NOT NULL,
+  ends_on    DATE NOT NULL,
+  opens_at   TIME NOT NULL,
+  closes_at  TIME NOT NULL,
+  created_by BIGINT NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  CHECK (ends_on >= starts_on),
+  CHECK (closes_at > opens_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_zone_schedules_zone ON zone_schedules (zone);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS zone_schedules;
+-- +goose StatementEnd
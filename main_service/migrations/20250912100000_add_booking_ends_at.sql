@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE bookings
+  ADD COLUMN ends_at TIMESTAMP;
+
+-- До появления правил длительности все брони длились два часа.
+UPDATE bookings SET ends_at = booking_time + INTERVAL '2 hours';
+
+ALTER TABLE bookings
+  ALTER COLUMN ends_at SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_bookings_ends_at ON bookings (ends_at) WHERE is_active = TRUE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_bookings_ends_at;
+
+ALTER TABLE bookings
+  DROP COLUMN ends_at;
+-- +goose StatementEnd
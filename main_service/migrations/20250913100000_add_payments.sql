@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS payments (
+  id          BIGSERIAL PRIMARY KEY,
+  booking_id  BIGINT NOT NULL REFERENCES bookings(id) ON DELETE CASCADE,
+  provider_id VARCHAR(100) NOT NULL UNIQUE,
+  amount      BIGINT NOT NULL CHECK (amount > 0),
+  currency    VARCHAR(3) NOT NULL,
+  status      VARCHAR(16) NOT NULL DEFAULT 'pending',
+  payment_url TEXT NOT NULL DEFAULT '',
+  expires_at  TIMESTAMP NOT NULL,
+  created_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+  updated_at  TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_payments_booking_id ON payments (booking_id);
+CREATE INDEX IF NOT EXISTS idx_payments_pending ON payments (expires_at) WHERE status = 'pending';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS payments;
+-- +goose StatementEnd
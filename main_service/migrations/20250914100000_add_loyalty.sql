@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS loyalty_transactions (
+  id         BIGSERIAL PRIMARY KEY,
+  user_id    BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  booking_id BIGINT REFERENCES bookings(id) ON DELETE SET NULL,
+  points     INTEGER NOT NULL CHECK (points <> 0),
+  kind       VARCHAR(16) NOT NULL,
+  reason     TEXT NOT NULL DEFAULT '',
+  created_by BIGINT,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_loyalty_transactions_user_id ON loyalty_transactions (user_id, id);
+-- Итог визита начисляет или списывает баллы по брони только один раз.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_loyalty_transactions_booking ON loyalty_transactions (booking_id, kind)
+  WHERE booking_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS loyalty_transactions;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS promo_codes (
+  id          BIGSERIAL PRIMARY KEY,
+  code        VARCHAR(32) NOT NULL UNIQUE,
+  description TEXT NOT NULL DEFAULT '',
+  valid_from  TIMESTAMP NOT NULL,
+  valid_until TIMESTAMP NOT NULL,
+  max_uses    INTEGER CHECK (max_uses > 0),
+  used_count  INTEGER NOT NULL DEFAULT 0 CHECK (used_count >= 0),
+  weekdays    TEXT[] NOT NULL DEFAULT '{}',
+  is_active   BOOLEAN NOT NULL DEFAULT TRUE,
+  created_by  BIGINT NOT NULL,
+  created_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+  CHECK (valid_until > valid_from)
+);
+
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS promo_code_id BIGINT REFERENCES promo_codes(id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE bookings DROP COLUMN IF EXISTS promo_code_id;
+DROP TABLE IF EXISTS promo_codes;
+-- +goose StatementEnd
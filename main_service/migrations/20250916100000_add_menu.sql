@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS menu_categories (
+  id          BIGSERIAL PRIMARY KEY,
+  name        VARCHAR(100) NOT NULL,
+  description TEXT NOT NULL DEFAULT '',
+  position    INTEGER NOT NULL DEFAULT 0,
+  is_active   BOOLEAN NOT NULL DEFAULT TRUE
+);
+
+CREATE TABLE IF NOT EXISTS dishes (
+  id           BIGSERIAL PRIMARY KEY,
+  category_id  BIGINT NOT NULL REFERENCES menu_categories(id),
+  name         VARCHAR(100) NOT NULL,
+  description  TEXT NOT NULL DEFAULT '',
+  price        BIGINT NOT NULL CHECK (price >= 0),
+  allergens    TEXT[] NOT NULL DEFAULT '{}',
+  is_available BOOLEAN NOT NULL DEFAULT TRUE,
+  position     INTEGER NOT NULL DEFAULT 0,
+  is_active    BOOLEAN NOT NULL DEFAULT TRUE
+);
+
+CREATE INDEX IF NOT EXISTS idx_dishes_category_id ON dishes (category_id) WHERE is_active = TRUE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS dishes;
+DROP TABLE IF EXISTS menu_categories;
+-- +goose StatementEnd
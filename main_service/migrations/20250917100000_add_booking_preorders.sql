@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS booking_preorders (
+  booking_id BIGINT NOT NULL REFERENCES bookings(id) ON DELETE CASCADE,
+  dish_id    BIGINT NOT NULL REFERENCES dishes(id),
+  name       VARCHAR(100) NOT NULL,
+  quantity   INTEGER NOT NULL CHECK (quantity > 0),
+  price      BIGINT NOT NULL,
+  PRIMARY KEY (booking_id, dish_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS booking_preorders;
+-- +goose StatementEnd
@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS special_events (
+  id             BIGSERIAL PRIMARY KEY,
+  title          VARCHAR(200) NOT NULL,
+  description    TEXT NOT NULL DEFAULT '',
+  starts_at      TIMESTAMP NOT NULL,
+  ends_at        TIMESTAMP NOT NULL,
+  capacity       INTEGER NOT NULL CHECK (capacity > 0),
+  seats_reserved INTEGER NOT NULL DEFAULT 0 CHECK (seats_reserved >= 0),
+  price          BIGINT NOT NULL CHECK (price >= 0),
+  is_active      BOOLEAN NOT NULL DEFAULT TRUE,
+  created_by     BIGINT NOT NULL,
+  created_at     TIMESTAMP NOT NULL DEFAULT NOW(),
+  CHECK (ends_at > starts_at),
+  CHECK (seats_reserved <= capacity)
+);
+
+CREATE INDEX IF NOT EXISTS idx_special_events_starts_at ON special_events (starts_at) WHERE is_active = TRUE;
+
+CREATE TABLE IF NOT EXISTS event_reservations (
+  id           BIGSERIAL PRIMARY KEY,
+  event_id     BIGINT NOT NULL REFERENCES special_events(id),
+  user_id      BIGINT NOT NULL,
+  seats        INTEGER NOT NULL CHECK (seats > 0),
+  amount       BIGINT NOT NULL CHECK (amount >= 0),
+  is_active    BOOLEAN NOT NULL DEFAULT TRUE,
+  created_at   TIMESTAMP NOT NULL DEFAULT NOW(),
+  cancelled_at TIMESTAMP
+);
+
+-- У гостя может быть только одна действующая резервация на событие, чтобы места не дробились.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_event_reservations_active
+  ON event_reservations (event_id, user_id) WHERE is_active = TRUE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS event_reservations;
+DROP TABLE IF EXISTS special_events;
+-- +goose StatementEnd
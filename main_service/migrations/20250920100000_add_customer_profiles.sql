@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS customer_profiles (
+  user_id    BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+  tags       TEXT[] NOT NULL DEFAULT '{}',
+  notes      TEXT NOT NULL DEFAULT '',
+  updated_by BIGINT NOT NULL,
+  updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_bookings_user_id ON bookings (user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_bookings_user_id;
+DROP TABLE IF EXISTS customer_profiles;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- tx_id - транзакция, записавшая событие. id выдаётся при вставке, а не при коммите,
+-- поэтому лента событий читается в порядке (tx_id, id) и только до самой старой открытой транзакции.
+ALTER TABLE booking_events ADD COLUMN IF NOT EXISTS tx_id XID8 NOT NULL DEFAULT pg_current_xact_id();
+CREATE INDEX IF NOT EXISTS idx_booking_events_tx_id ON booking_events (tx_id, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_booking_events_tx_id;
+ALTER TABLE booking_events DROP COLUMN IF EXISTS tx_id;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Доставки вебхуков теперь создаются в той же транзакции, что и событие брони, и курсор не нужен.
+-- События после курсора, которые worker ещё не успел разложить, ставятся в очередь здесь.
+INSERT INTO webhook_deliveries (webhook_id, event_id)
+SELECT w.id, e.id
+FROM booking_events e
+JOIN webhooks w ON w.is_active = TRUE AND e.event_type = ANY(w.event_types)
+WHERE e.id > (SELECT last_event_id FROM webhook_cursor WHERE id = 1)
+ON CONFLICT (webhook_id, event_id) DO NOTHING;
+
+DROP TABLE IF EXISTS webhook_cursor;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS webhook_cursor (
+  id            SMALLINT PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+  last_event_id BIGINT NOT NULL
+);
+
+INSERT INTO webhook_cursor (last_event_id)
+SELECT COALESCE(MAX(id), 0) FROM booking_events
+ON CONFLICT DO NOTHING;
+-- +goose StatementEnd
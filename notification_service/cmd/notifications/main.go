@@ -59,9 +59,19 @@ func startServer(ctx context.Context, cfg *config.Config, log *slog.Logger) {
 				return
 			}
 
-			subject, mesText := m.CreateMessege(emailMsg.UserID, emailMsg.TableID, emailMsg.BookingTime)
+			subject, mesText := m.CreateMessege(emailMsg)
+
+			// Решение по брони и просьбу об отзыве получает клиент, остальные уведомления - администратор.
+			to := cfg.AdministratorEmail
+			if emailMsg.Decision != "" || emailMsg.FeedbackURL != "" {
+				if emailMsg.Email == "" {
+					log.Error("no customer email in customer message", slog.Int64("bookingID", emailMsg.ID))
+					return
+				}
+				to = emailMsg.Email
+			}
 
-			err := m.Send(cfg.AdministratorEmail,
+			err := m.Send(to,
 				subject,
 				mesText,
 			)
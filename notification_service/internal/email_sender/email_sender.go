@@ -2,7 +2,9 @@ package emailsender
 
 import (
 	"fmt"
-	"time"
+	emailmodel "notification_service/internal/lib/models"
+	"strconv"
+	"strings"
 
 	"gopkg.in/gomail.v2"
 )
@@ -25,20 +27,178 @@ func (m *Mailer) Send(to, subject, body string) error {
 	return dialer.DialAndSend(msg)
 }
 
-func (m *Mailer) CreateMessege(userID, tableID int, bookingTime time.Time) (string, string) {
+// occasions - названия поводов визита для письма администратору.
+var occasions = map[string]string{
+	"birthday":    "день рождения",
+	"anniversary": "годовщина",
+	"business":    "деловая встреча",
+	"date":        "свидание",
+	"celebration": "праздник",
+	"other":       "другое",
+}
+
+// attributes - названия атрибутов места для письма администратору.
+var attributes = map[string]string{
+	"window":                "у окна",
+	"terrace":               "терраса",
+	"booth":                 "диванчик",
+	"quiet":                 "тихое место",
+	"wheelchair_accessible": "доступно для колясок",
+}
+
+const statusPendingApproval = "pending_approval"
+
+// Решения админа по брони, ожидающей подтверждения.
+const (
+	decisionApproved = "approved"
+	decisionRejected = "rejected"
+)
+
+func (m *Mailer) CreateMessege(msg emailmodel.EmailMessage) (string, string) {
 	var subject, messageText string
 
-	formattedTime := bookingTime.Format("02-01-2006 15:04:05")
+	formattedTime := msg.BookingTime.Format("02-01-2006 15:04:05")
+
+	table := fmt.Sprintf("Столик номер %d", msg.TableID)
+	if len(msg.TableIDs) > 1 {
+		ids := make([]string, 0, len(msg.TableIDs))
+		for _, id := range msg.TableIDs {
+			ids = append(ids, strconv.Itoa(id))
+		}
+		table = "Столики " + strings.Join(ids, ", ") + " (сдвинуты вместе)"
+	}
+
+	if msg.Decision != "" {
+		return decisionMessage(msg, table, formattedTime)
+	}
+
+	if msg.FeedbackURL != "" {
+		subject = "Как прошёл ваш визит?"
 
-	if userID == -1 {
+		messageText = fmt.Sprintf("Спасибо, что были у нас %s! Оцените, пожалуйста, визит по брони №%d:\n%s",
+			msg.BookingTime.Format("02-01-2006"), msg.ID, msg.FeedbackURL)
+
+		return subject, messageText
+	}
+
+	if msg.PreOrderChanged {
+		subject = "Изменён предзаказ"
+
+		messageText = fmt.Sprintf("Изменён предзаказ к брони №%d. %s. Дата и время: %s", msg.ID, table, formattedTime)
+
+		if len(msg.PreOrder) > 0 {
+			messageText += fmt.Sprintf("\nПредзаказ: %s", preOrderSummary(msg.PreOrder))
+		} else {
+			messageText += "\nПредзаказ отменён."
+		}
+
+		return subject, messageText
+	}
+
+	if msg.UserID == -1 {
 		subject = "Отмена брони"
 
-		messageText = fmt.Sprintf("Бронь отменена! Столик номер %d. Дата и время: %s", tableID, formattedTime)
+		messageText = fmt.Sprintf("Бронь отменена! %s. Дата и время: %s", table, formattedTime)
+
+		if msg.SeriesID != 0 {
+			subject = "Отмена повторяющейся брони"
+
+			messageText = fmt.Sprintf("Повторяющаяся бронь №%d отменена! %s. Отменено дат: %d, начиная с %s",
+				msg.SeriesID, table, msg.Occurrences, formattedTime)
+		}
 	} else {
 		subject = "Новая бронь"
 
-		messageText = fmt.Sprintf("Новая бронь! Столик номер %d. Дата и время: %s", tableID, formattedTime)
+		messageText = fmt.Sprintf("Новая бронь! %s. Дата и время: %s", table, formattedTime)
+
+		if msg.SeriesID != 0 {
+			subject = "Новая повторяющаяся бронь"
+
+			messageText = fmt.Sprintf("Новая повторяющаяся бронь №%d! %s. Забронировано дат: %d, первая: %s",
+				msg.SeriesID, table, msg.Occurrences, formattedTime)
+		}
+
+		if msg.Status == statusPendingApproval {
+			subject += " ждёт подтверждения"
+
+			messageText += fmt.Sprintf("\nБронь №%d ждёт подтверждения администратора.", msg.ID)
+		}
+
+		if msg.PartySize > 0 {
+			messageText += fmt.Sprintf("\nКоличество гостей: %d", msg.PartySize)
+		}
+
+		if msg.Occasion != "" {
+			occasion, ok := occasions[msg.Occasion]
+			if !ok {
+				occasion = msg.Occasion
+			}
+			messageText += fmt.Sprintf("\nПовод: %s", occasion)
+		}
+
+		if len(msg.Attributes) > 0 {
+			names := make([]string, 0, len(msg.Attributes))
+			for _, a := range msg.Attributes {
+				name, ok := attributes[a]
+				if !ok {
+					name = a
+				}
+				names = append(names, name)
+			}
+			messageText += fmt.Sprintf("\nМесто: %s", strings.Join(names, ", "))
+		}
+
+		if len(msg.Allergens) > 0 {
+			messageText += fmt.Sprintf("\nАллергены: %s", strings.Join(msg.Allergens, ", "))
+		}
+
+		if msg.Notes != "" {
+			messageText += fmt.Sprintf("\nПожелания гостя: %s", msg.Notes)
+		}
+
+		if msg.PromoCode != "" {
+			messageText += fmt.Sprintf("\nПромокод: %s", msg.PromoCode)
+			if msg.PromoDescription != "" {
+				messageText += fmt.Sprintf(" (%s)", msg.PromoDescription)
+			}
+		}
+
+		if len(msg.PreOrder) > 0 {
+			messageText += fmt.Sprintf("\nПредзаказ: %s", preOrderSummary(msg.PreOrder))
+		}
 	}
 
 	return subject, messageText
 }
+
+// decisionMessage составляет письмо клиенту о решении админа по его брони.
+func decisionMessage(msg emailmodel.EmailMessage, table, formattedTime string) (string, string) {
+	var subject, messageText string
+
+	switch msg.Decision {
+	case decisionApproved:
+		subject = "Бронь подтверждена"
+
+		messageText = fmt.Sprintf("Ваша бронь №%d подтверждена! %s. Дата и время: %s", msg.ID, table, formattedTime)
+	case decisionRejected:
+		subject = "Бронь отклонена"
+
+		messageText = fmt.Sprintf("К сожалению, ваша бронь №%d отклонена. %s. Дата и время: %s", msg.ID, table, formattedTime)
+
+		if msg.Reason != "" {
+			messageText += fmt.Sprintf("\nПричина: %s", msg.Reason)
+		}
+	}
+
+	return subject, messageText
+}
+
+// preOrderSummary перечисляет блюда предзаказа для кухни, например "Борщ × 2, Пельмени × 1".
+func preOrderSummary(items []emailmodel.PreOrderItem) string {
+	lines := make([]string, 0, len(items))
+	for _, item := range items {
+		lines = append(lines, fmt.Sprintf("%s × %d", item.Name, item.Quantity))
+	}
+
+	return strings.Join(lines, ", ")
+}
@@ -3,7 +3,40 @@ package emailmodel
 import "time"
 
 type EmailMessage struct {
+	ID          int64
 	UserID      int
 	TableID     int
+	TableIDs    []int
 	BookingTime time.Time
+	PartySize   int
+	Notes       string
+	Occasion    string
+	Allergens   []string
+	Attributes  []string
+	SeriesID    int64
+	Occurrences int
+	Status      string
+	// Decision, Reason и Email заполнены, если это уведомление клиенту о решении админа по брони.
+	Decision string
+	Reason   string
+	Email    string
+
+	// PromoCode и PromoDescription - промокод, применённый к брони, и что он даёт гостю.
+	PromoCode        string
+	PromoDescription string
+
+	// PreOrder - блюда, заказанные к брони. PreOrderChanged - это уведомление кухне об изменении предзаказа.
+	PreOrder        []PreOrderItem
+	PreOrderChanged bool
+
+	// FeedbackURL заполнен, если это просьба к клиенту оставить отзыв о визите.
+	FeedbackURL string
+}
+
+// PreOrderItem - позиция предзаказа: блюдо, количество и цена за порцию в копейках на момент заказа.
+type PreOrderItem struct {
+	DishID   int64  `json:"dish_id"`
+	Name     string `json:"name"`
+	Quantity int    `json:"quantity"`
+	Price    int64  `json:"price"`
 }